@@ -1,82 +1,172 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
+	"io/fs"
 	"os"
-	"path/filepath"
-	"strconv"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"weezel/jukeboxjury/internal/config"
 	"weezel/jukeboxjury/internal/game"
+	"weezel/jukeboxjury/internal/integration/transcribe"
 	"weezel/jukeboxjury/internal/logger"
 
 	"github.com/joho/godotenv"
-	_ "github.com/joho/godotenv/autoload"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-var flagConfigFile string
-
-func loadConfigFile() {
-	flag.StringVar(&flagConfigFile, "f", "", "Default config file location")
-	flag.Parse()
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "replay" {
+		replay(os.Args[2:])
+		return
+	}
 
-	configFileAbs, err := filepath.Abs(flagConfigFile)
+	lookupEnv, err := dotenvLookup(".env")
 	if err != nil {
-		logger.Logger.Fatal().Err(err).Msg("Cannot get absolute path of config file")
+		logger.Logger.Fatal().Err(err).Msg("Cannot read .env file")
 	}
-
-	if err = godotenv.Load(configFileAbs); err != nil {
-		logger.Logger.Fatal().Err(err).Msg("Cannot load config file")
+	cfg, err := config.Load(os.Args[1:], lookupEnv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		logger.Logger.Fatal().Err(err).Msg("Invalid configuration")
 	}
 
-	logger.Logger.Info().Msgf("Config file %s succesfully loaded", configFileAbs)
-}
-
-func main() {
-	loadConfigFile()
-
-	tgramAPI, err := tgbotapi.NewBotAPI(os.Getenv("BOT_API_TOKEN"))
+	tgramAPI, err := tgbotapi.NewBotAPI(cfg.Telegram.Token)
 	if err != nil {
 		logger.Logger.Fatal().Err(err).Msg("Failed to create new bot")
 	}
 
-	chatID, err := strconv.ParseInt(os.Getenv("CHAT_ID"), 10, 64)
-	if err != nil {
-		logger.Logger.Fatal().Err(err).Msg("Invalid chat ID")
+	// Validated already by config.Load
+	timeZone, _ := time.LoadLocation(cfg.Chat.TimeZone)
+	audienceMode, _ := game.ParseAudienceMode(cfg.Game.AudienceJurors)
+	language, _ := game.ParseLanguage(cfg.Chat.Language)
+	aliases, _ := game.ParseCommandAliases(cfg.Commands.Aliases)
+	revealStyle, _ := game.ParseRevealStyle(cfg.Game.Reveal)
+	judgeScoring, _ := game.ParseJudgeScoring(cfg.Game.JudgeScoring)
+	opts := []game.PlayOption{
+		game.WithOutputDirectory(&cfg.Results.Directory),
+		game.WithTimeZone(timeZone),
+		game.WithTimeFormat(cfg.Chat.TimeFormat),
+		game.WithLanguage(language),
+		game.WithPrefix(cfg.Commands.Prefix),
+		game.WithCommandAliases(aliases),
+		game.WithRatingScale(cfg.Game.RatingMax),
+		game.WithBotAdmins(cfg.Telegram.BotAdmins...),
+		game.WithAudienceJurors(audienceMode),
+		game.WithAudiencePoll(cfg.Game.AudiencePoll),
+		game.WithScoreboard(cfg.Game.Scoreboard),
+		game.WithHostCommentary(cfg.Game.HostCommentary),
+		game.WithReveal(revealStyle, cfg.Game.RevealDelay),
+		game.WithScorePredictions(cfg.Game.ScorePredictions),
+		game.WithJudgeScoring(judgeScoring),
+		game.WithTournament(cfg.Game.Tournament),
+		game.WithElimination(cfg.Game.Elimination),
+		game.WithTeams(cfg.Game.Teams),
+		game.WithEventLog(cfg.Game.EventLogDirectory),
+	}
+	if cfg.Game.TranscribeCommand != "" {
+		transcriber, err := transcribe.NewCommand(cfg.Game.TranscribeCommand)
+		if err != nil {
+			logger.Logger.Fatal().Err(err).Msg("Invalid transcription command")
+		}
+		opts = append(opts, game.WithTranscriber(transcriber, cfg.Timeouts.Transcribe))
 	}
+	if cfg.Results.URL != "" {
+		resultsURL, _ := cfg.ResultsURL()
+		opts = append(opts, game.WithResultsURL(resultsURL))
+	}
+	p := game.New(tgramAPI, cfg.Chat.ID, opts...)
+	if err = p.RegisterCommands(); err != nil {
+		logger.Logger.Warn().Err(err).Msg("Failed to register commands, clients won't suggest them")
+	}
+	parser := p.Parser(tgramAPI.Self.UserName)
 
-	resultsDir := os.Getenv("RESULTS_DIRECTORY")
-	p := game.New(
-		tgramAPI,
-		chatID,
-		game.WithOutputDirectory(&resultsDir),
-		game.WithResultsURL(os.Getenv("RESULTS_URL")),
-	)
-	state := p.StartGame
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	engine := game.NewEngine(p)
+	engineDone := make(chan struct{})
+	go func() {
+		defer close(engineDone)
+		if err := engine.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			logger.Logger.Error().Err(err).Msg("Game engine failed")
+		}
+	}()
 
 	u := tgbotapi.NewUpdate(0)
-	u.Timeout = 30
+	u.Timeout = int(cfg.Timeouts.Updates.Seconds())
+	updates := tgramAPI.GetUpdatesChan(u)
 	logger.Logger.Info().Msg("Waiting for messages...")
-	for update := range tgramAPI.GetUpdatesChan(u) {
-		if update.Message == nil {
+	receiveUpdates(ctx, engine, parser, updates)
+
+	logger.Logger.Info().Msg("Shutting down")
+	tgramAPI.StopReceivingUpdates()
+	// Engine finishes the message in process and the voice reviews being
+	// transcribed, and interrupts the game. Messages are sent synchronously,
+	// so nothing is left to flush. Transcriptions which don't finish in time
+	// are dropped.
+	select {
+	case <-engineDone:
+		logger.Logger.Info().Msg("Bye")
+	case <-time.After(cfg.Timeouts.Shutdown):
+		logger.Logger.Error().Msg("Game didn't shut down in time, exiting anyway")
+	}
+}
+
+// dotenvLookup looks up the environment variables, falling back to the
+// ones set in the .env file. A missing file sets no variables.
+func dotenvLookup(fpath string) (func(string) (string, bool), error) {
+	dotenv, err := godotenv.Read(fpath)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	return func(key string) (string, bool) {
+		if value, ok := os.LookupEnv(key); ok {
+			return value, true
+		}
+		value, ok := dotenv[key]
+		return value, ok
+	}, nil
+}
+
+// receiveUpdates passes the received messages to the engine until
+// the context is cancelled.
+func receiveUpdates(ctx context.Context, engine *game.Engine, parser game.Parser, updates tgbotapi.UpdatesChannel) {
+	for {
+		var update tgbotapi.Update
+		select {
+		case <-ctx.Done():
+			return
+		case update = <-updates:
+		}
+		if update.Message == nil && update.CallbackQuery == nil {
 			continue
 		}
 
-		msg, err := game.ParseToMessage(tgbotapi.Update{Message: update.Message})
+		msg, err := parser.Parse(update)
 		if err != nil {
 			logger.Logger.Debug().Err(err).
-				Interface("payload", update.Message.Text).
+				Interface("payload", update.Message).
 				Msg("Failed to parse message")
+			if update.FromChat() == nil {
+				continue
+			}
+			if err = engine.ExplainParseError(ctx, update.FromChat().ID, err); err != nil {
+				logger.Logger.Error().Err(err).Msg("Failed to explain parse error")
+			}
 			continue
 		}
 
-		state = state(msg)
-		if state == nil || msg.Command == game.CommandStop {
-			p.ClearGame()
-			logger.Logger.Info().Msg("All good, getting back to the start state")
-			state = p.StartGame
-			continue
+		if err = engine.Submit(ctx, msg); err != nil {
+			logger.Logger.Error().Err(err).Interface("msg", msg).Msg("Failed to submit message")
 		}
 	}
 }
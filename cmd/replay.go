@@ -0,0 +1,43 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"weezel/jukeboxjury/internal/game"
+	"weezel/jukeboxjury/internal/integration/telegram"
+	"weezel/jukeboxjury/internal/logger"
+)
+
+// replay rebuilds a game from its event log and prints the messages
+// the bot sent and the final results. The game is replayed with the
+// options it was logged with.
+func replay(args []string) {
+	flags := flag.NewFlagSet("replay", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s replay <event log>\n", os.Args[0])
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(args) // ExitOnError
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	fin, err := os.Open(flags.Arg(0))
+	if err != nil {
+		logger.Logger.Fatal().Err(err).Msg("Cannot open event log")
+	}
+	defer fin.Close()
+
+	events, err := game.ReadEvents(fin)
+	if err != nil {
+		logger.Logger.Fatal().Err(err).Msg("Cannot read event log")
+	}
+
+	_, err = game.Replay(events, telegram.NewFakeBot(os.Stdout), game.WithOutputDirectory(nil))
+	if err != nil {
+		logger.Logger.Warn().Err(err).Msg("Replayed game diverged from the event log")
+	}
+}
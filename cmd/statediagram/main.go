@@ -0,0 +1,60 @@
+// Command statediagram regenerates the mermaid state diagram in README.md
+// from the game's transition table.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"weezel/jukeboxjury/internal/game"
+	"weezel/jukeboxjury/internal/logger"
+)
+
+var (
+	mermaidStart = []byte("```mermaid\n")
+	mermaidEnd   = []byte("```")
+)
+
+var errNoDiagram = errors.New("no mermaid block found")
+
+func replaceDiagram(doc []byte, diagram string) ([]byte, error) {
+	start := bytes.Index(doc, mermaidStart)
+	if start == -1 {
+		return nil, errNoDiagram
+	}
+	start += len(mermaidStart)
+
+	end := bytes.Index(doc[start:], mermaidEnd)
+	if end == -1 {
+		return nil, fmt.Errorf("unterminated mermaid block: %w", errNoDiagram)
+	}
+	end += start
+
+	out := make([]byte, 0, len(doc)+len(diagram))
+	out = append(out, doc[:start]...)
+	out = append(out, diagram...)
+	return append(out, doc[end:]...), nil
+}
+
+func main() {
+	readme := flag.String("readme", "README.md", "Markdown file containing the state diagram")
+	flag.Parse()
+
+	doc, err := os.ReadFile(*readme)
+	if err != nil {
+		logger.Logger.Fatal().Err(err).Msgf("Failed to read %s", *readme)
+	}
+
+	updated, err := replaceDiagram(doc, game.MermaidDiagram())
+	if err != nil {
+		logger.Logger.Fatal().Err(err).Msgf("Failed to update the diagram in %s", *readme)
+	}
+
+	//nolint:gosec // Documentation is meant to be world readable
+	if err = os.WriteFile(*readme, updated, 0o644); err != nil {
+		logger.Logger.Fatal().Err(err).Msgf("Failed to write %s", *readme)
+	}
+}
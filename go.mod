@@ -3,6 +3,7 @@ module weezel/jukeboxjury
 go 1.23.1
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/go-telegram-bot-api/telegram-bot-api/v5 v5.5.1
 	github.com/rs/zerolog v1.33.0
 )
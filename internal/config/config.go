@@ -0,0 +1,544 @@
+// Package config loads the bot configuration from a TOML file. Every value
+// can be overridden by an environment variable and a command line flag,
+// in that order of precedence.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"weezel/jukeboxjury/internal/game"
+
+	"github.com/BurntSushi/toml"
+)
+
+type Telegram struct {
+	Token     string  `toml:"token"`
+	BotAdmins []int64 `toml:"bot_admins"`
+}
+
+type Chat struct {
+	TimeZone   string `toml:"time_zone"`
+	TimeFormat string `toml:"time_format"`
+	Language   string `toml:"language"`
+	ID         int64  `toml:"id"`
+}
+
+type Results struct {
+	Directory string `toml:"directory"`
+	URL       string `toml:"url"`
+}
+
+type Commands struct {
+	// Aliases are extra words for the commands, keyed by the command.
+	Aliases map[string][]string `toml:"aliases"`
+	Prefix  string              `toml:"prefix"`
+}
+
+type Game struct {
+	AudienceJurors    string        `toml:"audience_jurors"`
+	EventLogDirectory string        `toml:"event_log_directory"`
+	Reveal            string        `toml:"reveal"`
+	JudgeScoring      string        `toml:"judge_scoring"`
+	TranscribeCommand string        `toml:"transcribe_command"`
+	RevealDelay       time.Duration `toml:"reveal_delay"`
+	RatingMax         int           `toml:"rating_max"`
+	AudiencePoll      bool          `toml:"audience_poll"`
+	Scoreboard        bool          `toml:"scoreboard"`
+	HostCommentary    bool          `toml:"host_commentary"`
+	ScorePredictions  bool          `toml:"score_predictions"`
+	Tournament        bool          `toml:"tournament"`
+	Elimination       bool          `toml:"elimination"`
+	Teams             bool          `toml:"teams"`
+}
+
+type Timeouts struct {
+	// Updates is the long polling timeout of Telegram updates.
+	Updates time.Duration `toml:"updates"`
+	// Shutdown is how long the running game is given to shut down.
+	Shutdown time.Duration `toml:"shutdown"`
+	// Transcribe is how long a voice review may take to transcribe.
+	Transcribe time.Duration `toml:"transcribe"`
+}
+
+type Config struct {
+	Telegram Telegram `toml:"telegram"`
+	Results  Results  `toml:"results"`
+	Commands Commands `toml:"commands"`
+	Chat     Chat     `toml:"chat"`
+	Game     Game     `toml:"game"`
+	Timeouts Timeouts `toml:"timeouts"`
+}
+
+// Default returns the configuration used for the values which are not set.
+func Default() Config {
+	return Config{
+		Chat:     Chat{TimeZone: "Europe/Helsinki", TimeFormat: game.DefaultTimeFormat, Language: "en"},
+		Commands: Commands{Prefix: game.JukeboxJuryPrefix},
+		Game:     Game{RatingMax: 10, RevealDelay: game.DefaultRevealDelay},
+		Timeouts: Timeouts{
+			Updates:    30 * time.Second,
+			Shutdown:   10 * time.Second,
+			Transcribe: game.DefaultTranscribeTimeout,
+		},
+	}
+}
+
+// setting binds a configuration value to its environment variable and flag.
+type setting struct {
+	value func(c *Config) flag.Value
+	key   string
+	env   string
+	flag  string
+	usage string
+}
+
+var settings = []setting{
+	{
+		key: "telegram.token", env: "BOT_API_TOKEN", flag: "token",
+		usage: "Telegram bot API token",
+		value: func(c *Config) flag.Value { return (*stringValue)(&c.Telegram.Token) },
+	},
+	{
+		key: "telegram.bot_admins", env: "BOT_ADMINS", flag: "bot-admins",
+		usage: "comma separated Telegram user IDs of the bot admins",
+		value: func(c *Config) flag.Value { return (*int64ListValue)(&c.Telegram.BotAdmins) },
+	},
+	{
+		key: "chat.id", env: "CHAT_ID", flag: "chat-id",
+		usage: "chat where the game is played",
+		value: func(c *Config) flag.Value { return (*int64Value)(&c.Chat.ID) },
+	},
+	{
+		key: "chat.time_zone", env: "TIME_ZONE", flag: "time-zone",
+		usage: "time zone of the timestamps, e.g. Europe/Helsinki",
+		value: func(c *Config) flag.Value { return (*stringValue)(&c.Chat.TimeZone) },
+	},
+	{
+		key: "chat.time_format", env: "TIME_FORMAT", flag: "time-format",
+		usage: "layout of the timestamps in Go's time format, e.g. 2006-01-02 15:04",
+		value: func(c *Config) flag.Value { return (*stringValue)(&c.Chat.TimeFormat) },
+	},
+	{
+		key: "chat.language", env: "CHAT_LANGUAGE", flag: "language",
+		usage: "language of the replies and commands, en or fi",
+		value: func(c *Config) flag.Value { return (*stringValue)(&c.Chat.Language) },
+	},
+	{
+		key: "commands.prefix", env: "COMMAND_PREFIX", flag: "command-prefix",
+		usage: "word which starts the commands",
+		value: func(c *Config) flag.Value { return (*stringValue)(&c.Commands.Prefix) },
+	},
+	{
+		key: "commands.aliases", env: "COMMAND_ALIASES", flag: "command-aliases",
+		usage: "extra words for the commands, e.g. join:mukaan,review:pisteet",
+		value: func(c *Config) flag.Value { return (*aliasesValue)(&c.Commands.Aliases) },
+	},
+	{
+		key: "results.directory", env: "RESULTS_DIRECTORY", flag: "results-directory",
+		usage: "directory where to save result HTMLs",
+		value: func(c *Config) flag.Value { return (*stringValue)(&c.Results.Directory) },
+	},
+	{
+		key: "results.url", env: "RESULTS_URL", flag: "results-url",
+		usage: "prefix for the results URL",
+		value: func(c *Config) flag.Value { return (*stringValue)(&c.Results.URL) },
+	},
+	{
+		key: "game.rating_max", env: "RATING_MAX", flag: "rating-max",
+		usage: "highest rating of a review",
+		value: func(c *Config) flag.Value { return (*intValue)(&c.Game.RatingMax) },
+	},
+	{
+		key: "game.audience_jurors", env: "AUDIENCE_JURORS", flag: "audience-jurors",
+		usage: "late joiners' ratings: counted, separate or empty to disable",
+		value: func(c *Config) flag.Value { return (*stringValue)(&c.Game.AudienceJurors) },
+	},
+	{
+		key: "game.audience_poll", env: "AUDIENCE_POLL", flag: "audience-poll",
+		usage: "post a rating poll for the audience with each song",
+		value: func(c *Config) flag.Value { return (*boolValue)(&c.Game.AudiencePoll) },
+	},
+	{
+		key: "game.scoreboard", env: "SCOREBOARD", flag: "scoreboard",
+		usage: "pin a scoreboard message which is edited as the game progresses",
+		value: func(c *Config) flag.Value { return (*boolValue)(&c.Game.Scoreboard) },
+	},
+	{
+		key: "game.reveal", env: "REVEAL", flag: "reveal",
+		usage: "how reviews are revealed: consolidated or paced",
+		value: func(c *Config) flag.Value { return (*stringValue)(&c.Game.Reveal) },
+	},
+	{
+		key: "game.reveal_delay", env: "REVEAL_DELAY", flag: "reveal-delay",
+		usage: "delay between the reviews of a paced reveal",
+		value: func(c *Config) flag.Value { return (*durationValue)(&c.Game.RevealDelay) },
+	},
+	{
+		key: "game.host_commentary", env: "HOST_COMMENTARY", flag: "host-commentary",
+		usage: "let the presenter comment on their song before it is reviewed",
+		value: func(c *Config) flag.Value { return (*boolValue)(&c.Game.HostCommentary) },
+	},
+	{
+		key: "game.score_predictions", env: "SCORE_PREDICTIONS", flag: "score-predictions",
+		usage: "let the panelists predict the average score of their own song",
+		value: func(c *Config) flag.Value { return (*boolValue)(&c.Game.ScorePredictions) },
+	},
+	{
+		key: "game.tournament", env: "TOURNAMENT", flag: "tournament",
+		usage: "let the chat run a knockout tournament between the songs of the previous games",
+		value: func(c *Config) flag.Value { return (*boolValue)(&c.Game.Tournament) },
+	},
+	{
+		key: "game.elimination", env: "ELIMINATION", flag: "elimination",
+		usage: "eliminate the panelist with the lowest score after each round until one remains",
+		value: func(c *Config) flag.Value { return (*boolValue)(&c.Game.Elimination) },
+	},
+	{
+		key: "game.teams", env: "TEAMS", flag: "teams",
+		usage: "let the panelists join teams, which don't review their own songs and are scored together",
+		value: func(c *Config) flag.Value { return (*boolValue)(&c.Game.Teams) },
+	},
+	{
+		key: "game.judge_scoring", env: "JUDGE_SCORING", flag: "judge-scoring",
+		usage: "score reviewers by how close they rated to the songs' average or median, empty to disable",
+		value: func(c *Config) flag.Value { return (*stringValue)(&c.Game.JudgeScoring) },
+	},
+	{
+		key: "game.transcribe_command", env: "TRANSCRIBE_COMMAND", flag: "transcribe-command",
+		usage: "command which prints the transcript of the voice review file given as its last argument",
+		value: func(c *Config) flag.Value { return (*stringValue)(&c.Game.TranscribeCommand) },
+	},
+	{
+		key: "game.event_log_directory", env: "EVENT_LOG_DIRECTORY", flag: "event-log-directory",
+		usage: "directory where to save game event logs, empty to disable",
+		value: func(c *Config) flag.Value { return (*stringValue)(&c.Game.EventLogDirectory) },
+	},
+	{
+		key: "timeouts.updates", env: "UPDATES_TIMEOUT", flag: "updates-timeout",
+		usage: "long polling timeout of Telegram updates",
+		value: func(c *Config) flag.Value { return (*durationValue)(&c.Timeouts.Updates) },
+	},
+	{
+		key: "timeouts.shutdown", env: "SHUTDOWN_TIMEOUT", flag: "shutdown-timeout",
+		usage: "how long the running game is given to shut down",
+		value: func(c *Config) flag.Value { return (*durationValue)(&c.Timeouts.Shutdown) },
+	},
+	{
+		key: "timeouts.transcribe", env: "TRANSCRIBE_TIMEOUT", flag: "transcribe-timeout",
+		usage: "how long a voice review may take to transcribe",
+		value: func(c *Config) flag.Value { return (*durationValue)(&c.Timeouts.Transcribe) },
+	},
+}
+
+// Load reads the configuration file given with the -f flag, overrides its
+// values with the environment variables and the rest of the flags and
+// validates the result. All the problems found are returned together.
+func Load(args []string, lookupEnv func(string) (string, bool)) (Config, error) {
+	cfg := Default()
+
+	var configFile string
+	fs := flag.NewFlagSet("jukeboxjury", flag.ContinueOnError)
+	fs.StringVar(&configFile, "f", "", "TOML configuration file")
+	overrides := map[string]string{}
+	for _, s := range settings {
+		usage := fmt.Sprintf("%s (%s, env %s)", s.usage, s.key, s.env)
+		override := func(value string) error {
+			overrides[s.flag] = value
+			return nil
+		}
+		if _, ok := s.value(&cfg).(interface{ IsBoolFlag() bool }); ok {
+			fs.BoolFunc(s.flag, usage, override)
+		} else {
+			fs.Func(s.flag, usage, override)
+		}
+	}
+	if err := fs.Parse(args); err != nil {
+		return cfg, fmt.Errorf("parse flags: %w", err)
+	}
+
+	var errs []error
+	if configFile != "" {
+		undecoded, err := decodeFile(configFile, &cfg)
+		if err != nil {
+			return cfg, err
+		}
+		errs = append(errs, undecoded...)
+	}
+
+	for _, s := range settings {
+		if value, ok := lookupEnv(s.env); ok {
+			if err := s.value(&cfg).Set(value); err != nil {
+				errs = append(errs, fmt.Errorf("environment variable %s: %w", s.env, err))
+			}
+		}
+	}
+	for _, s := range settings {
+		if value, ok := overrides[s.flag]; ok {
+			if err := s.value(&cfg).Set(value); err != nil {
+				errs = append(errs, fmt.Errorf("flag -%s: %w", s.flag, err))
+			}
+		}
+	}
+	errs = append(errs, cfg.Validate())
+
+	return cfg, errors.Join(errs...)
+}
+
+// decodeFile reads the configuration file into cfg. Unknown keys, which
+// are most likely typos, are returned as errors.
+func decodeFile(fpath string, cfg *Config) ([]error, error) {
+	meta, err := toml.DecodeFile(fpath, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %w", fpath, err)
+	}
+
+	var errs []error
+	for _, key := range meta.Undecoded() {
+		errs = append(errs, fmt.Errorf("config file %s: unknown key %s", fpath, key))
+	}
+	return errs, nil
+}
+
+// Validate checks every value and returns all the problems found.
+func (c Config) Validate() error {
+	var errs []error
+	invalid := func(key string, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: %s", key, fmt.Sprintf(format, args...)))
+	}
+
+	if c.Telegram.Token == "" {
+		invalid("telegram.token", "missing")
+	}
+	if c.Chat.ID == 0 {
+		invalid("chat.id", "missing")
+	}
+	if _, err := time.LoadLocation(c.Chat.TimeZone); err != nil {
+		invalid("chat.time_zone", "%v", err)
+	}
+	// A layout without any date or time elements formats as is
+	if time.Unix(0, 0).Format(c.Chat.TimeFormat) == c.Chat.TimeFormat {
+		invalid("chat.time_format", "%q has no date or time elements", c.Chat.TimeFormat)
+	}
+	if _, err := game.ParseLanguage(c.Chat.Language); err != nil {
+		invalid("chat.language", "%v", err)
+	}
+	if c.Commands.Prefix == "" || strings.ContainsFunc(c.Commands.Prefix, unicode.IsSpace) {
+		invalid("commands.prefix", "must be a single word, got %q", c.Commands.Prefix)
+	}
+	if _, err := game.ParseCommandAliases(c.Commands.Aliases); err != nil {
+		invalid("commands.aliases", "%v", err)
+	}
+	if c.Results.Directory != "" {
+		if err := isDirectory(c.Results.Directory); err != nil {
+			invalid("results.directory", "%v", err)
+		}
+	}
+	if c.Results.URL != "" {
+		if _, err := c.ResultsURL(); err != nil {
+			invalid("results.url", "%v", err)
+		}
+	}
+	if c.Game.RatingMax < 1 {
+		invalid("game.rating_max", "must be at least 1, got %d", c.Game.RatingMax)
+	}
+	if c.Game.AudiencePoll && c.Game.RatingMax > game.MaxPollRating {
+		invalid("game.rating_max", "audience poll supports ratings up to %d, got %d",
+			game.MaxPollRating, c.Game.RatingMax)
+	}
+	if c.Game.AudiencePoll && c.Game.RatingMax < 2 {
+		invalid("game.rating_max", "audience poll needs ratings up to at least 2, got %d", c.Game.RatingMax)
+	}
+	if _, err := game.ParseAudienceMode(c.Game.AudienceJurors); err != nil {
+		invalid("game.audience_jurors", "%v", err)
+	}
+	if _, err := game.ParseRevealStyle(c.Game.Reveal); err != nil {
+		invalid("game.reveal", "%v", err)
+	}
+	if c.Game.RevealDelay <= 0 {
+		invalid("game.reveal_delay", "must be positive, got %s", c.Game.RevealDelay)
+	}
+	if _, err := game.ParseJudgeScoring(c.Game.JudgeScoring); err != nil {
+		invalid("game.judge_scoring", "%v", err)
+	}
+	if args := strings.Fields(c.Game.TranscribeCommand); len(args) > 0 {
+		if _, err := exec.LookPath(args[0]); err != nil {
+			invalid("game.transcribe_command", "%v", err)
+		}
+	}
+	if c.Game.EventLogDirectory != "" {
+		if err := isDirectory(c.Game.EventLogDirectory); err != nil {
+			invalid("game.event_log_directory", "%v", err)
+		}
+	}
+	if c.Timeouts.Updates < time.Second {
+		invalid("timeouts.updates", "must be at least a second, got %s", c.Timeouts.Updates)
+	}
+	if c.Timeouts.Shutdown <= 0 {
+		invalid("timeouts.shutdown", "must be positive, got %s", c.Timeouts.Shutdown)
+	}
+	if c.Timeouts.Transcribe <= 0 {
+		invalid("timeouts.transcribe", "must be positive, got %s", c.Timeouts.Transcribe)
+	}
+
+	return errors.Join(errs...)
+}
+
+// ResultsURL parses the prefix of the results URL.
+func (c Config) ResultsURL() (*url.URL, error) {
+	resultsURL, err := url.Parse(c.Results.URL)
+	if err != nil {
+		return nil, fmt.Errorf("parse results URL: %w", err)
+	}
+	if resultsURL.Scheme != "http" && resultsURL.Scheme != "https" || resultsURL.Host == "" {
+		return nil, fmt.Errorf("%q is not an absolute HTTP URL", c.Results.URL)
+	}
+	return resultsURL, nil
+}
+
+func isDirectory(fpath string) error {
+	info, err := os.Stat(fpath)
+	if err != nil {
+		return fmt.Errorf("check directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", fpath)
+	}
+	return nil
+}
+
+type stringValue string
+
+func (s *stringValue) Set(value string) error {
+	*s = stringValue(value)
+	return nil
+}
+
+func (s *stringValue) String() string { return string(*s) }
+
+type intValue int
+
+func (i *intValue) Set(value string) error {
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("parse integer: %w", err)
+	}
+	*i = intValue(parsed)
+	return nil
+}
+
+func (i *intValue) String() string { return strconv.Itoa(int(*i)) }
+
+type int64Value int64
+
+func (i *int64Value) Set(value string) error {
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("parse integer: %w", err)
+	}
+	*i = int64Value(parsed)
+	return nil
+}
+
+func (i *int64Value) String() string { return strconv.FormatInt(int64(*i), 10) }
+
+type int64ListValue []int64
+
+func (l *int64ListValue) Set(value string) error {
+	parsed := []int64{}
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		i, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return fmt.Errorf("parse integer list: %w", err)
+		}
+		parsed = append(parsed, i)
+	}
+	*l = parsed
+	return nil
+}
+
+func (l *int64ListValue) String() string {
+	fields := make([]string, 0, len(*l))
+	for _, i := range *l {
+		fields = append(fields, strconv.FormatInt(i, 10))
+	}
+	return strings.Join(fields, ",")
+}
+
+// aliasesValue is a comma separated list of command:alias pairs.
+type aliasesValue map[string][]string
+
+func (a *aliasesValue) Set(value string) error {
+	parsed := aliasesValue{}
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		command, alias, found := strings.Cut(field, ":")
+		if !found {
+			return fmt.Errorf("parse alias %q: expected command:alias", field)
+		}
+		command = strings.TrimSpace(command)
+		parsed[command] = append(parsed[command], strings.TrimSpace(alias))
+	}
+	*a = parsed
+	return nil
+}
+
+func (a *aliasesValue) String() string {
+	fields := []string{}
+	for command, aliases := range *a {
+		for _, alias := range aliases {
+			fields = append(fields, command+":"+alias)
+		}
+	}
+	slices.Sort(fields)
+	return strings.Join(fields, ",")
+}
+
+type boolValue bool
+
+func (b *boolValue) Set(value string) error {
+	if value == "" {
+		*b = false
+		return nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return fmt.Errorf("parse boolean: %w", err)
+	}
+	*b = boolValue(parsed)
+	return nil
+}
+
+func (b *boolValue) String() string { return strconv.FormatBool(bool(*b)) }
+
+// IsBoolFlag allows the flag to be given without a value.
+func (b *boolValue) IsBoolFlag() bool { return true }
+
+type durationValue time.Duration
+
+func (d *durationValue) Set(value string) error {
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("parse duration: %w", err)
+	}
+	*d = durationValue(parsed)
+	return nil
+}
+
+func (d *durationValue) String() string { return time.Duration(*d).String() }
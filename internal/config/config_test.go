@@ -0,0 +1,176 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	fpath := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(fpath, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return fpath
+}
+
+func envMap(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+}
+
+func TestLoadOverrides(t *testing.T) {
+	resultsDir := t.TempDir()
+	fpath := writeConfig(t, `
+[telegram]
+token = "file-token"
+bot_admins = [1, 2]
+
+[chat]
+id = -100
+time_zone = "UTC"
+language = "fi"
+
+[commands]
+prefix = "jj"
+
+[commands.aliases]
+join = ["mukaan"]
+
+[results]
+directory = "`+resultsDir+`"
+url = "https://example.com/jj"
+
+[game]
+rating_max = 5
+audience_jurors = "counted"
+scoreboard = true
+reveal = "paced"
+host_commentary = true
+judge_scoring = "median"
+score_predictions = true
+tournament = true
+elimination = true
+teams = true
+
+[timeouts]
+updates = "1m"
+transcribe = "30s"
+`)
+
+	cfg, err := Load(
+		[]string{"-f", fpath, "-chat-id", "-300", "-audience-poll"},
+		envMap(map[string]string{
+			"CHAT_ID":            "-200",
+			"BOT_ADMINS":         "3, 4",
+			"SHUTDOWN_TIMEOUT":   "5s",
+			"COMMAND_ALIASES":    "join:mukaan, review:pisteet",
+			"REVEAL_DELAY":       "2s",
+			"TRANSCRIBE_COMMAND": "echo",
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	want := Config{
+		Telegram: Telegram{Token: "file-token", BotAdmins: []int64{3, 4}},
+		Chat:     Chat{ID: -300, TimeZone: "UTC", TimeFormat: "2006-01-02 15:04", Language: "fi"},
+		Results:  Results{Directory: resultsDir, URL: "https://example.com/jj"},
+		Commands: Commands{
+			Prefix:  "jj",
+			Aliases: map[string][]string{"join": {"mukaan"}, "review": {"pisteet"}},
+		},
+		Game: Game{
+			RatingMax: 5, AudienceJurors: "counted", AudiencePoll: true, Scoreboard: true,
+			Reveal: "paced", RevealDelay: 2 * time.Second, HostCommentary: true,
+			JudgeScoring: "median", ScorePredictions: true, Tournament: true, Elimination: true,
+			Teams: true, TranscribeCommand: "echo",
+		},
+		Timeouts: Timeouts{Updates: time.Minute, Shutdown: 5 * time.Second, Transcribe: 30 * time.Second},
+	}
+	if diff := cmp.Diff(want, cfg); diff != "" {
+		t.Errorf("Load() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLoadReportsAllErrors(t *testing.T) {
+	fpath := writeConfig(t, `
+[chat]
+id = 1
+time_zone = "Mars/Olympus_Mons"
+time_format = "yesterday"
+language = "sv"
+
+[commands]
+prefix = "two words"
+aliases = { dance = ["tanssi"] }
+
+[results]
+url = "example.com/jj"
+
+[game]
+rating_max = 20
+audience_poll = true
+audience_jurors = "everybody"
+reveal = "slowly"
+reveal_delay = "0s"
+judge_scoring = "closest"
+transcribe_command = "no-such-transcriber --model base"
+typo = true
+`)
+
+	_, err := Load([]string{"-f", fpath, "-updates-timeout", "soon"}, envMap(nil))
+	if err == nil {
+		t.Fatal("Load() succeeded, want an error")
+	}
+
+	for _, want := range []string{
+		"unknown key game.typo",
+		"flag -updates-timeout",
+		"telegram.token: missing",
+		"chat.time_zone",
+		"chat.time_format",
+		"chat.language",
+		"commands.prefix",
+		"commands.aliases",
+		"results.url",
+		"game.rating_max: audience poll supports ratings up to 10",
+		"game.audience_jurors",
+		"game.reveal: unknown reveal style",
+		"game.reveal_delay: must be positive",
+		"game.judge_scoring: unknown judge scoring",
+		"game.transcribe_command",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Load() error doesn't mention %q:\n%v", want, err)
+		}
+	}
+}
+
+func TestAudiencePollNeedsTwoRatings(t *testing.T) {
+	fpath := writeConfig(t, `
+[telegram]
+token = "token"
+
+[chat]
+id = 1
+
+[game]
+rating_max = 1
+audience_poll = true
+`)
+
+	_, err := Load([]string{"-f", fpath}, envMap(nil))
+	want := "game.rating_max: audience poll needs ratings up to at least 2, got 1"
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Errorf("Load() error = %v, want %q", err, want)
+	}
+}
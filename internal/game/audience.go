@@ -0,0 +1,187 @@
+package game
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"weezel/jukeboxjury/internal/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// AudienceMode controls whether users joining after the join phase
+// can take part as audience jurors and how their ratings are counted.
+type AudienceMode int
+
+const (
+	// AudienceDisabled rejects late joiners.
+	AudienceDisabled AudienceMode = iota
+	// AudienceCounted counts audience ratings into the song's average score.
+	AudienceCounted
+	// AudienceSeparate tallies audience ratings as a separate audience score.
+	AudienceSeparate
+)
+
+// ParseAudienceMode parses the mode from its configuration value. An empty
+// value disables audience jurors.
+func ParseAudienceMode(mode string) (AudienceMode, error) {
+	switch strings.ToLower(mode) {
+	case "", "disabled":
+		return AudienceDisabled, nil
+	case "counted":
+		return AudienceCounted, nil
+	case "separate":
+		return AudienceSeparate, nil
+	}
+	return AudienceDisabled, fmt.Errorf("unknown audience jurors mode %q, expected counted or separate", mode)
+}
+
+// WithAudienceJurors lets late joiners review songs as audience jurors.
+func WithAudienceJurors(mode AudienceMode) PlayOption {
+	return func(p *Play) {
+		p.audienceMode = mode
+	}
+}
+
+// WithAudiencePoll posts a Telegram poll for the channel audience with
+// each introduced song. Poll votes are tallied into the audience score.
+func WithAudiencePoll(enabled bool) PlayOption {
+	return func(p *Play) {
+		p.audiencePoll = enabled
+	}
+}
+
+// MaxPollRating is the highest rating scale an audience poll can offer.
+// Telegram allows at most ten poll options, hence the zero is left out
+// on a scale of ten.
+const MaxPollRating = 10
+
+// pollOptions are the ratings the audience can vote for, from zero up
+// unless the scale has more ratings than a poll has options.
+func (p *Play) pollOptions() []string {
+	lowest := 0
+	if p.ratingMax >= MaxPollRating {
+		lowest = 1
+	}
+	options := make([]string, 0, p.ratingMax+1)
+	for rating := lowest; rating <= min(p.ratingMax, MaxPollRating); rating++ {
+		options = append(options, strconv.Itoa(rating))
+	}
+	return options
+}
+
+// openAudiencePoll posts a poll about the current song to the channel.
+func (p *Play) openAudiencePoll() {
+	if !p.audiencePoll {
+		return
+	}
+
+	poll := tgbotapi.NewPoll(
+		p.chatID,
+		p.tr(msgPollQuestion, p.host.Name).plain(),
+		p.pollOptions()...,
+	)
+	sent, err := p.bot.Send(poll)
+	if err != nil {
+		logger.Logger.Error().Err(err).Str("host_name", p.host.Name).Msg("Error sending audience poll")
+		return
+	}
+	p.pollMessageID = sent.MessageID
+}
+
+// closeAudiencePoll stops the open poll and adds its votes to the
+// audience score of the song. Nothing is done when no poll is open.
+func (p *Play) closeAudiencePoll(song *Song) {
+	if p.pollMessageID == 0 {
+		return
+	}
+	defer func() { p.pollMessageID = 0 }()
+
+	poll, err := p.bot.StopPoll(tgbotapi.NewStopPoll(p.chatID, p.pollMessageID))
+	if err != nil {
+		logger.Logger.Error().Err(err).Int("poll_message_id", p.pollMessageID).
+			Msg("Error stopping audience poll")
+		return
+	}
+	p.recordEvent(Event{Type: EventPoll, Poll: &poll})
+	if song == nil {
+		return
+	}
+
+	sum := song.AudienceScore * float64(song.AudienceVotes)
+	for _, option := range poll.Options {
+		rating, err := strconv.Atoi(option.Text)
+		if err != nil {
+			logger.Logger.Warn().Err(err).Str("option", option.Text).Msg("Unknown audience poll option")
+			continue
+		}
+		sum += float64(rating * option.VoterCount)
+		song.AudienceVotes += option.VoterCount
+	}
+	if song.AudienceVotes > 0 {
+		song.AudienceScore = sum / float64(song.AudienceVotes)
+	}
+
+	logger.Logger.Info().
+		Float64("song_audience_score", song.AudienceScore).
+		Int("song_audience_votes", song.AudienceVotes).
+		Msgf("Audience poll closed for the song %s", song.URL)
+}
+
+// addAudienceJuror adds a late joiner as an audience juror. Audience jurors
+// can review songs, but they don't present songs and the game doesn't wait
+// for their reviews.
+func (p *Play) addAudienceJuror(msg Message) {
+	if p.audienceMode == AudienceDisabled {
+		p.sendMessageToPanelist(msg.ChatID, p.tr(msgGameAlreadyStarted))
+		return
+	}
+
+	if p.findReviewer(msg.FromID) != nil {
+		p.sendMessageToPanelist(msg.ChatID, p.tr(msgAlreadyInGame))
+		return
+	}
+
+	juror := NewPanelist(msg.PlayerName, msg.FromID)
+	juror.audience = true
+	p.AudienceJurors = append(p.AudienceJurors, juror)
+
+	logger.Logger.Info().Msgf("Audience juror %s with ID %d joined the game", msg.PlayerName, msg.FromID)
+	p.announceProgress(p.tr(msgJoinedAudience, bold(msg.PlayerName)))
+}
+
+// findReviewer returns the panelist or audience juror with the given ID.
+func (p *Play) findReviewer(uid int64) *Panelist {
+	for _, rev := range p.Panelists {
+		if rev.uid == uid {
+			return rev
+		}
+	}
+	for _, rev := range p.AudienceJurors {
+		if rev.uid == uid {
+			return rev
+		}
+	}
+
+	return nil
+}
+
+// averageRating returns the average of the ratings and the number of
+// ratings counted. Audience reviews are included when audience is true,
+// panelist reviews when panel is true.
+func averageRating(reviews []*Review, panel bool, audience bool) (float64, int) {
+	sum, count := 0, 0
+	for _, r := range reviews {
+		if (r.Audience && !audience) || (!r.Audience && !panel) {
+			continue
+		}
+		sum += r.Rating
+		count++
+	}
+	if count == 0 {
+		return 0, 0
+	}
+
+	return float64(sum) / float64(count), count
+}
@@ -0,0 +1,70 @@
+package game
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAudiencePoll(t *testing.T) {
+	var stoppedPollID int
+	mockBot := mockTelegramBot{
+		mSend: func(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+			if _, ok := c.(tgbotapi.SendPollConfig); ok {
+				return tgbotapi.Message{MessageID: 42}, nil
+			}
+			return tgbotapi.Message{}, nil
+		},
+		mStopPoll: func(c tgbotapi.StopPollConfig) (tgbotapi.Poll, error) {
+			stoppedPollID = c.MessageID
+			return tgbotapi.Poll{
+				Options: []tgbotapi.PollOption{
+					{Text: "1", VoterCount: 0},
+					{Text: "5", VoterCount: 1},
+					{Text: "8", VoterCount: 2},
+				},
+			}, nil
+		},
+		receivedMessages: []string{},
+	}
+
+	p := New(&mockBot, 1, WithOutputDirectory(nil), WithAudiencePoll(true))
+	p.host = NewPanelist("Santana", 666)
+	p.openAudiencePoll()
+	if p.pollMessageID != 42 {
+		t.Fatalf("Poll message ID = %d, want 42", p.pollMessageID)
+	}
+
+	// One audience juror has already given 1/10
+	song := &Song{URL: "https://example.com/1", AudienceScore: 1, AudienceVotes: 1}
+	p.closeAudiencePoll(song)
+	if stoppedPollID != 42 {
+		t.Errorf("Stopped poll ID = %d, want 42", stoppedPollID)
+	}
+	if p.pollMessageID != 0 {
+		t.Errorf("Poll is still open with ID %d", p.pollMessageID)
+	}
+	if song.AudienceVotes != 4 {
+		t.Errorf("Audience votes = %d, want 4", song.AudienceVotes)
+	}
+	if song.AudienceScore != 5.5 {
+		t.Errorf("Audience score = %.2f, want 5.50", song.AudienceScore)
+	}
+}
+
+func TestPollOptions(t *testing.T) {
+	for _, tt := range []struct {
+		want      []string
+		ratingMax int
+	}{
+		{want: []string{"0", "1", "2"}, ratingMax: 2},
+		{want: []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}, ratingMax: 9},
+		{want: []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10"}, ratingMax: 10},
+	} {
+		p := New(&mockTelegramBot{}, 1, WithOutputDirectory(nil), WithRatingScale(tt.ratingMax))
+		if diff := cmp.Diff(tt.want, p.pollOptions()); diff != "" {
+			t.Errorf("Poll options on a scale of %d mismatch (-want +got):\n%s", tt.ratingMax, diff)
+		}
+	}
+}
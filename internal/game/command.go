@@ -0,0 +1,116 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Telegram accepts only these characters in the registered commands
+var slashCommandPattern = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+
+// WithPrefix sets the word which starts the commands, JukeboxJuryPrefix
+// by default. The prefix is shown in the replies, the messages are
+// parsed with a Parser using the same prefix.
+func WithPrefix(prefix string) PlayOption {
+	return func(p *Play) {
+		p.prefix = prefix
+	}
+}
+
+// WithCommandAliases adds aliases to the commands, see ParseCommandAliases.
+func WithCommandAliases(aliases map[string][]string) PlayOption {
+	return func(p *Play) {
+		p.customAliases = aliases
+	}
+}
+
+// ParseCommandAliases checks the configured command aliases. The commands
+// can be named with any of their built-in words, e.g. join or liity. The
+// returned aliases are keyed by the command.
+func ParseCommandAliases(aliases map[string][]string) (map[string][]string, error) {
+	builtin := commandsFor(LanguageEnglish, nil)
+	parsed := map[string][]string{}
+	taken := map[string]string{}
+	var errs []error
+	for name, words := range aliases {
+		command, ok := builtin[strings.ToLower(name)]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown command %q", name))
+			continue
+		}
+		for _, word := range words {
+			word = strings.ToLower(word)
+			if word == "" || strings.ContainsFunc(word, unicode.IsSpace) {
+				errs = append(errs, fmt.Errorf("alias %q of %s must be a single word", word, name))
+				continue
+			}
+			other, ok := builtin[word]
+			if !ok {
+				other, ok = taken[word]
+			}
+			if ok && other != command {
+				errs = append(errs, fmt.Errorf("alias %q of %s is already a command", word, name))
+				continue
+			}
+			taken[word] = command
+			parsed[command] = append(parsed[command], word)
+		}
+	}
+
+	return parsed, errors.Join(errs...)
+}
+
+// slashCommandNames returns the words of the command usable as Telegram
+// slash commands: the one in the language of the game and the configured
+// aliases.
+func (p *Play) slashCommandNames(command string) []string {
+	names := []string{}
+	for _, lang := range []Language{p.language, LanguageEnglish} {
+		i := slices.IndexFunc(commandAliases[lang][command], slashCommandPattern.MatchString)
+		if i != -1 {
+			names = append(names, commandAliases[lang][command][i])
+			break
+		}
+	}
+	for _, alias := range p.customAliases[command] {
+		if slashCommandPattern.MatchString(alias) && !slices.Contains(names, alias) {
+			names = append(names, alias)
+		}
+	}
+	return names
+}
+
+// RegisterCommands registers the commands with Telegram, so that the
+// clients can suggest them while typing.
+func (p *Play) RegisterCommands() error {
+	helps := []commandHelp{
+		helpStart, helpJoin, helpContinue, helpPresent, helpReview,
+		helpKick, helpSkip, helpStop, helpHelp, helpStatus,
+	}
+	if p.hostCommentary {
+		helps = append(helps, helpComment)
+	}
+	if p.tournaments {
+		helps = append(helps, helpTour, helpVote)
+	}
+	commands := make([]tgbotapi.BotCommand, 0, len(helps))
+	for _, help := range helps {
+		for _, name := range p.slashCommandNames(help.command) {
+			commands = append(commands, tgbotapi.BotCommand{
+				Command:     name,
+				Description: p.tr(help.description).plain(),
+			})
+		}
+	}
+
+	if _, err := p.bot.Request(tgbotapi.NewSetMyCommands(commands...)); err != nil {
+		return fmt.Errorf("set my commands: %w", err)
+	}
+	return nil
+}
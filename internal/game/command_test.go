@@ -0,0 +1,102 @@
+package game
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseCommandAliases(t *testing.T) {
+	got, err := ParseCommandAliases(map[string][]string{"join": {"Mukaan"}, "arvioi": {"pisteet"}})
+	if err != nil {
+		t.Fatalf("ParseCommandAliases() error: %v", err)
+	}
+	want := map[string][]string{CommandJoin: {"mukaan"}, CommandReview: {"pisteet"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ParseCommandAliases() mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, aliases := range []map[string][]string{
+		{"dance": {"tanssi"}},
+		{"join": {"two words"}},
+		{"join": {"stop"}},
+		{"join": {"mukaan"}, "stop": {"mukaan"}},
+	} {
+		if _, err := ParseCommandAliases(aliases); err == nil {
+			t.Errorf("ParseCommandAliases(%v) succeeded, want an error", aliases)
+		}
+	}
+}
+
+func TestCustomPrefixAndAliases(t *testing.T) {
+	mockBot := mockTelegramBot{
+		mSend: func(_ tgbotapi.Chattable) (tgbotapi.Message, error) {
+			return tgbotapi.Message{}, nil
+		},
+		receivedMessages: []string{},
+	}
+
+	santana := &tgbotapi.User{ID: 666, UserName: "Santana"}
+	jesus := &tgbotapi.User{ID: 123, UserName: "Jesus"}
+	pjotr := &tgbotapi.User{ID: 7, UserName: "Pjotr"}
+	updates := []tgbotapi.Message{
+		testMessage(santana, "/start"),
+		testMessage(jesus, "jj mukaan"),
+		testMessage(pjotr, "/mukaan@JukeboxBot"),
+	}
+
+	p := New(&mockBot, 1,
+		WithOutputDirectory(nil),
+		WithPrefix("jj"),
+		WithCommandAliases(map[string][]string{CommandJoin: {"mukaan"}}),
+	)
+	parser := p.Parser("JukeboxBot")
+	for i, update := range updates {
+		msg, err := parser.Parse(tgbotapi.Update{Message: &update})
+		if err != nil {
+			t.Fatalf("Failed to parse %d %q: %#v", i, update.Text, err)
+		}
+		p.Handle(msg)
+	}
+
+	expected := []string{
+		"User <b>Santana</b> started a new game, join by using command: jj liity",
+		"User <b>Jesus</b> joined the game",
+		"User <b>Pjotr</b> joined the game",
+	}
+	if diff := cmp.Diff(expected, mockBot.receivedMessages); diff != "" {
+		t.Errorf("Messages mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestRegisterCommands(t *testing.T) {
+	mockBot := mockTelegramBot{}
+	p := New(&mockBot, 1, WithLanguage(LanguageFinnish),
+		WithCommandAliases(map[string][]string{CommandJoin: {"mukaan", "tulen-mukaan"}}))
+	if err := p.RegisterCommands(); err != nil {
+		t.Fatalf("RegisterCommands() error: %v", err)
+	}
+	if len(mockBot.requests) != 1 {
+		t.Fatalf("RegisterCommands() sent %d requests, want 1", len(mockBot.requests))
+	}
+	config, ok := mockBot.requests[0].(tgbotapi.SetMyCommandsConfig)
+	if !ok {
+		t.Fatalf("RegisterCommands() sent %T, want SetMyCommandsConfig", mockBot.requests[0])
+	}
+
+	var commands []string
+	for _, command := range config.Commands {
+		if command.Description == "" {
+			t.Errorf("Command %q has no description", command.Command)
+		}
+		commands = append(commands, command.Command)
+	}
+	// Aliases which aren't valid slash commands are left out
+	want := []string{
+		"aloita", "liity", "mukaan", "jatka", "esitys", "arvioi", "potki", "ohita", "lopeta", "apua", "tila",
+	}
+	if diff := cmp.Diff(want, commands); diff != "" {
+		t.Errorf("Registered commands mismatch (-want +got):\n%s", diff)
+	}
+}
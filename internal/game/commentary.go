@@ -0,0 +1,94 @@
+package game
+
+import (
+	"slices"
+
+	"weezel/jukeboxjury/internal/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// WithHostCommentary lets the presenter of each song tell why they chose
+// it before the song is reviewed.
+func WithHostCommentary(enabled bool) PlayOption {
+	return func(p *Play) {
+		p.hostCommentary = enabled
+	}
+}
+
+// waitForCommentary waits for the presenter's note on the song. The note
+// can be skipped by the presenter or by those allowed to skip songs, so
+// that an absent presenter doesn't stall the game.
+func (p *Play) waitForCommentary(msg Message) State {
+	logger.Logger.Debug().Msg("State: Wait for the host's commentary")
+
+	switch msg.Command {
+	case CommandKick:
+		if !p.Authorize(msg, ActionKick) {
+			return StateWaitForCommentary
+		}
+		host := p.host
+		if !p.kickPanelist(msg) {
+			return StateInit
+		}
+		if !slices.Contains(p.Panelists, host) {
+			return p.nextRound()
+		}
+		return StateWaitForCommentary
+	case CommandSkip:
+		if msg.FromID != p.host.uid && !p.Authorize(msg, ActionSkip) {
+			return StateWaitForCommentary
+		}
+		logger.Logger.Info().Msgf("Panelist %s with ID %d skipped the commentary", msg.PlayerName, msg.FromID)
+		p.sendMessageToChannel(p.tr(msgNoCommentary, bold(p.host.Name)))
+		return StateWaitForReviews
+	case CommandJoin:
+		p.addAudienceJuror(msg)
+		return StateWaitForCommentary
+	case CommandComment:
+	default:
+		p.sendMessageToPanelist(msg.ChatID, p.tr(msgWaitForComment, bold(p.host.Name)))
+		return StateWaitForCommentary
+	}
+
+	if msg.FromID != p.host.uid {
+		p.sendMessageToPanelist(msg.ChatID, p.tr(msgNotTheHost))
+		return StateWaitForCommentary
+	}
+	if msg.Text == "" && msg.Voice == "" {
+		p.sendMessageToPanelist(msg.ChatID, p.tr(msgCommentMissing))
+		return StateWaitForCommentary
+	}
+
+	p.host.Song.Commentary = msg.Text
+	p.host.Song.VoiceNote = msg.Voice
+	logger.Logger.Info().
+		Str("host_name", p.host.Name).
+		Str("commentary", msg.Text).
+		Bool("voice_note", msg.Voice != "").
+		Msgf("Panelist %s commented on the song %s", msg.PlayerName, p.host.Song.URL)
+	p.postCommentary(p.host)
+
+	return StateWaitForReviews
+}
+
+// postCommentary posts the host's note to the channel. A voice note is
+// forwarded as a voice message with the written note as its caption.
+func (p *Play) postCommentary(host *Panelist) {
+	text := p.tr(msgCommentary, bold(host.Name), host.Song.Commentary)
+	if host.Song.VoiceNote == "" {
+		p.sendMessageToChannel(text)
+		return
+	}
+
+	if host.Song.Commentary == "" {
+		text = p.tr(msgVoiceCommentary, bold(host.Name))
+	}
+	voice := tgbotapi.NewVoice(p.chatID, tgbotapi.FileID(host.Song.VoiceNote))
+	voice.Caption = string(text)
+	voice.ParseMode = tgbotapi.ModeHTML
+	if _, err := p.bot.Send(voice); err != nil {
+		logger.Logger.Error().Err(err).Msg("Error sending the voice commentary")
+		p.sendMessageToChannel(text)
+	}
+}
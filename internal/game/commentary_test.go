@@ -0,0 +1,106 @@
+package game
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestHostCommentary(t *testing.T) {
+	p, bot := newScriptedGame(t, WithOutputDirectory(nil), WithHostCommentary(true))
+	var voices []tgbotapi.VoiceConfig
+	bot.mSend = func(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+		if voice, ok := c.(tgbotapi.VoiceConfig); ok {
+			voices = append(voices, voice)
+		}
+		return tgbotapi.Message{}, nil
+	}
+
+	santana := &tgbotapi.User{ID: 666, UserName: "Santana"}
+	jesus := &tgbotapi.User{ID: 123, UserName: "Jesus"}
+	pjotr := &tgbotapi.User{ID: 7, UserName: "Pjotr"}
+	voiceNote := testMessage(pjotr, "levyraati kommentoi")
+	voiceNote.ReplyToMessage = &tgbotapi.Message{Voice: &tgbotapi.Voice{FileID: "voice-1"}, From: pjotr}
+
+	runScript(t, p, bot, []scriptStep{
+		{update: testMessage(santana, "levyraati aloita"), want: StateWaitPanelistsToJoin},
+		{update: testMessage(jesus, "levyraati liity"), want: StateWaitPanelistsToJoin},
+		{update: testMessage(pjotr, "levyraati liity"), want: StateWaitPanelistsToJoin},
+		{update: testMessage(santana, "levyraati jatka"), want: StateAddSong},
+		{update: testMessage(santana, "levyraati esitä Song1 https://example.com/1"), want: StateAddSong},
+		{update: testMessage(jesus, "levyraati esitä Song2 https://example.com/2"), want: StateAddSong},
+		{
+			update: testMessage(pjotr, "levyraati esitä Song3 https://example.com/3"),
+			want:   StateWaitForCommentary,
+			response: "<b>Santana</b>, tell why you chose the song with: " +
+				"levyraati kommentoi &lt;commentary&gt;, or reply with it to a voice note",
+		},
+		{
+			update:   testMessage(jesus, "levyraati arvioi Good 8/10"),
+			want:     StateWaitForCommentary,
+			response: "Wait for <b>Santana</b> to comment on the song first",
+		},
+		{
+			update:   testMessage(jesus, "levyraati kommentoi I like it"),
+			want:     StateWaitForCommentary,
+			response: "Only the panelist who chose the song can comment on it",
+		},
+		{
+			update:   testMessage(santana, "levyraati kommentoi"),
+			want:     StateWaitForCommentary,
+			response: "Write the commentary after the command, or reply with it to a voice note",
+		},
+		{
+			update:   testMessage(santana, "levyraati kommentoi Reminds me of <summer>"),
+			want:     StateWaitForReviews,
+			response: "<b>Santana</b> on why they chose the song: Reminds me of &lt;summer&gt;",
+		},
+		{update: testMessage(jesus, "levyraati arvioi Good 8/10"), want: StateWaitForReviews},
+		{update: testMessage(pjotr, "levyraati arvioi Fine 6/10"), want: StateWaitForCommentary},
+		{
+			update:   testMessage(jesus, "levyraati ohita"),
+			want:     StateWaitForReviews,
+			response: "No commentary from <b>Jesus</b>, review the song",
+		},
+		{update: testMessage(santana, "levyraati arvioi Good 8/10"), want: StateWaitForReviews},
+		{update: testMessage(pjotr, "levyraati arvioi Fine 6/10"), want: StateWaitForCommentary},
+		{update: voiceNote, want: StateWaitForReviews},
+	})
+
+	if len(voices) != 1 {
+		t.Fatalf("Sent %d voice notes, want 1", len(voices))
+	}
+	voice := voices[0]
+	if voice.File != tgbotapi.FileID("voice-1") || voice.Caption != "<b>Pjotr</b> on why they chose the song" {
+		t.Errorf("Voice commentary = %q with caption %q", voice.File, voice.Caption)
+	}
+
+	want := []*Song{
+		{Commentary: "Reminds me of <summer>"},
+		{},
+		{VoiceNote: "voice-1"},
+	}
+	got := make([]*Song, 0, len(p.Panelists))
+	for _, panelist := range p.Panelists {
+		got = append(got, &Song{Commentary: panelist.Song.Commentary, VoiceNote: panelist.Song.VoiceNote})
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Commentaries mismatch (-want +got):\n%s", diff)
+	}
+
+	var out bytes.Buffer
+	if err := renderResults(*p, &out); err != nil {
+		t.Fatalf("renderResults() error: %v", err)
+	}
+	for _, expected := range []string{
+		"<strong>Presenter's commentary:</strong> Reminds me of &lt;summer&gt;",
+		"<strong>Presenter's commentary:</strong> (voice note)",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Results don't contain %q", expected)
+		}
+	}
+}
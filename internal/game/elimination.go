@@ -0,0 +1,157 @@
+package game
+
+import (
+	"cmp"
+	"slices"
+
+	"weezel/jukeboxjury/internal/logger"
+)
+
+// WithElimination plays the game in rounds. Once every song of the round
+// has been reviewed, the panelist whose song got the lowest average score
+// is eliminated. The eliminated panelists keep reviewing but don't add
+// songs, and the rounds go on until one panelist remains.
+func WithElimination(enabled bool) PlayOption {
+	return func(p *Play) {
+		p.elimination = enabled
+	}
+}
+
+// EliminationRound is a finished round of an elimination game.
+type EliminationRound struct {
+	Eliminated string       // Name of the eliminated panelist
+	Songs      []songResult // By the average score, the highest first
+	Number     int
+}
+
+// contestants returns the panelists who haven't been eliminated.
+func (p *Play) contestants() []*Panelist {
+	contestants := []*Panelist{}
+	for _, panelist := range p.Panelists {
+		if panelist.EliminatedIn == 0 {
+			contestants = append(contestants, panelist)
+		}
+	}
+	return contestants
+}
+
+// eliminate ends the round by eliminating the contestant with the lowest
+// score, see breakTie for when several share it. Returns the state of the
+// next round, or StateStopGame once a winner remains.
+func (p *Play) eliminate() State {
+	contestants := p.contestants()
+	round := EliminationRound{Number: len(p.EliminationRounds) + 1}
+	for _, contestant := range contestants {
+		round.Songs = append(round.Songs, songResult{
+			Song:       contestant.Song,
+			Panelist:   contestant.Name,
+			Reviews:    contestant.ReceivedReviews,
+			PanelistID: contestant.uid,
+		})
+	}
+	slices.SortStableFunc(round.Songs, func(a, b songResult) int {
+		return cmp.Compare(b.Song.AverageScore, a.Song.AverageScore)
+	})
+
+	if len(contestants) > 1 {
+		lowest := round.Songs[len(round.Songs)-1]
+		tied := []songResult{}
+		for _, song := range round.Songs {
+			if song.Song.AverageScore == lowest.Song.AverageScore {
+				tied = append(tied, song)
+			}
+		}
+		if len(tied) > 1 {
+			lowest = p.breakTie(tied)
+		}
+
+		eliminated := contestants[slices.IndexFunc(contestants, func(pan *Panelist) bool {
+			return pan.uid == lowest.PanelistID
+		})]
+		eliminated.EliminatedIn = round.Number
+		round.Eliminated = eliminated.Name
+		logger.Logger.Info().
+			Int("round", round.Number).
+			Float64("song_average_score", lowest.Song.AverageScore).
+			Msgf("Panelist %s with ID %d was eliminated", eliminated.Name, eliminated.uid)
+		p.sendMessageToChannel(p.tr(msgEliminated, bold(eliminated.Name),
+			songLink(lowest.Song), lowest.Song.AverageScore, len(contestants)-1))
+	}
+	p.EliminationRounds = append(p.EliminationRounds, round)
+
+	contestants = p.contestants()
+	if len(contestants) <= 1 {
+		return StateStopGame
+	}
+
+	// The eliminated panelists count as having presented their song so
+	// that only the contestants add and present songs in the next round
+	names := make([]string, 0, len(contestants))
+	for _, contestant := range contestants {
+		contestant.SongSubmitted = false
+		contestant.SongPresented = false
+		contestant.ReceivedReviews = []*Review{}
+		names = append(names, contestant.Name)
+	}
+	for _, pan := range p.Panelists {
+		pan.ReviewGiven = false
+	}
+	for _, juror := range p.AudienceJurors {
+		juror.ReviewGiven = false
+	}
+	p.allSongsSubmitted = false
+	p.sendMessageToChannel(p.tr(msgEliminationRound,
+		round.Number+1, p.joinOrNone(names), p.prefix, p.commandName(CommandPresent)))
+
+	return StateAddSong
+}
+
+// breakTie picks the song to eliminate among the songs sharing the lowest
+// score. The panelist who scored the least in the earlier rounds goes out.
+// When that's a tie too, a draw with the seeded random generator decides,
+// so that a replay draws the same panelist.
+func (p *Play) breakTie(tied []songResult) songResult {
+	earlier := func(song songResult) float64 {
+		total := 0.0
+		for _, round := range p.EliminationRounds {
+			for _, played := range round.Songs {
+				if played.PanelistID == song.PanelistID {
+					total += played.Song.AverageScore
+				}
+			}
+		}
+		return total
+	}
+
+	names := make([]string, 0, len(tied))
+	for _, song := range tied {
+		names = append(names, song.Panelist)
+	}
+	least := slices.MinFunc(tied, func(a, b songResult) int {
+		return cmp.Compare(earlier(a), earlier(b))
+	})
+	candidates := slices.DeleteFunc(slices.Clone(tied), func(song songResult) bool {
+		return earlier(song) != earlier(least)
+	})
+	if len(candidates) == 1 {
+		p.sendMessageToChannel(p.tr(msgEliminationTie, p.joinOrNone(names), least.Song.AverageScore))
+		return least
+	}
+
+	p.sendMessageToChannel(p.tr(msgEliminationDraw, p.joinOrNone(names), least.Song.AverageScore))
+	return candidates[p.rng.IntN(len(candidates))]
+}
+
+// rankByElimination orders the panelists by how long they lasted, the
+// winner first. Those who lasted equally long keep their order.
+func (p *Play) rankByElimination() {
+	lasted := func(panelist *Panelist) int {
+		if panelist.EliminatedIn == 0 {
+			return len(p.EliminationRounds) + 1
+		}
+		return panelist.EliminatedIn
+	}
+	slices.SortStableFunc(p.Panelists, func(a, b *Panelist) int {
+		return cmp.Compare(lasted(b), lasted(a))
+	})
+}
@@ -0,0 +1,155 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"weezel/jukeboxjury/internal/integration/telegram"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// eliminationRounds plays an elimination game where Pjotr is eliminated
+// in the first round and Santana and Jesus tie in the second one. Jesus'
+// song gets the given rating from Pjotr in the first round.
+func eliminationRounds(jesusRating string) []scriptStep {
+	santana := &tgbotapi.User{ID: 666, UserName: "Santana"}
+	jesus := &tgbotapi.User{ID: 123, UserName: "Jesus"}
+	pjotr := &tgbotapi.User{ID: 7, UserName: "Pjotr"}
+	return []scriptStep{
+		{update: testMessage(santana, "levyraati aloita"), want: StateWaitPanelistsToJoin},
+		{update: testMessage(jesus, "levyraati liity"), want: StateWaitPanelistsToJoin},
+		{update: testMessage(pjotr, "levyraati liity"), want: StateWaitPanelistsToJoin},
+		{update: testMessage(santana, "levyraati jatka"), want: StateAddSong},
+		// Round 1
+		{update: testMessage(santana, "levyraati esitä Song1 https://example.com/1"), want: StateAddSong},
+		{update: testMessage(jesus, "levyraati esitä Song2 https://example.com/2"), want: StateAddSong},
+		{update: testMessage(pjotr, "levyraati esitä Song3 https://example.com/3"), want: StateWaitForReviews},
+		{update: testMessage(jesus, "levyraati arvioi Good 8/10"), want: StateWaitForReviews},
+		{update: testMessage(pjotr, "levyraati arvioi Fine 6/10"), want: StateWaitForReviews},
+		{update: testMessage(santana, "levyraati arvioi Bland 4/10"), want: StateWaitForReviews},
+		{update: testMessage(pjotr, "levyraati arvioi Rating "+jesusRating), want: StateWaitForReviews},
+		{update: testMessage(santana, "levyraati arvioi Average 5/10"), want: StateWaitForReviews},
+		{
+			update: testMessage(jesus, "levyraati arvioi Average 5/10"),
+			want:   StateAddSong,
+			response: "Elimination round 2: Santana, Jesus, add your next songs with: levyraati esitä " +
+				"description here https://link-as-last-item",
+		},
+		// Round 2 ends in a tie
+		{
+			update:   testMessage(pjotr, "levyraati esitä Song4 https://example.com/4"),
+			want:     StateAddSong,
+			response: "You have been eliminated, but you can still review the songs",
+		},
+		{update: testMessage(santana, "levyraati esitä Song5 https://example.com/5"), want: StateAddSong},
+		{update: testMessage(jesus, "levyraati esitä Song6 https://example.com/6"), want: StateWaitForReviews},
+		{update: testMessage(jesus, "levyraati arvioi Fine 6/10"), want: StateWaitForReviews},
+		{update: testMessage(pjotr, "levyraati arvioi Fine 6/10"), want: StateWaitForReviews},
+		{update: testMessage(santana, "levyraati arvioi Fine 6/10"), want: StateWaitForReviews},
+		{update: testMessage(pjotr, "levyraati arvioi Fine 6/10"), want: StateInit},
+	}
+}
+
+func TestElimination(t *testing.T) {
+	resultsDir := t.TempDir()
+	p, bot := newScriptedGame(t, WithOutputDirectory(&resultsDir), WithElimination(true))
+	runScript(t, p, bot, eliminationRounds("8/10"))
+
+	sent := strings.Join(bot.receivedMessages, "\n")
+	for _, expected := range []string{
+		"<b>Pjotr</b> is eliminated, <a href=\"https://example.com/3\">Song3</a> got the lowest score 5.00. " +
+			"2 panelists remain, the eliminated ones keep reviewing",
+		"Santana, Jesus share the lowest score 6.00, the scores of the earlier rounds break the tie",
+		"<b>Jesus</b> is eliminated, <a href=\"https://example.com/6\">Song6</a> got the lowest score 6.00",
+		"The winner song came from <b>Santana</b>",
+	} {
+		if !strings.Contains(sent, expected) {
+			t.Errorf("Messages don't contain %q", expected)
+		}
+	}
+
+	results, err := filepath.Glob(filepath.Join(resultsDir, "jukebox_jury_results_*.html"))
+	if err != nil || len(results) != 1 {
+		t.Fatalf("Expected a single results file, got %v: %v", results, err)
+	}
+	html, err := os.ReadFile(results[0])
+	if err != nil {
+		t.Fatalf("Failed to read results: %v", err)
+	}
+	for _, expected := range []string{
+		"<h2>Santana</h2>\n        <div class=\"song\">",
+		"<h2>Jesus</h2>\n        <p class=\"eliminated\">Eliminated in round 2</p>",
+		"<h2>Pjotr</h2>\n        <p class=\"eliminated\">Eliminated in round 1</p>",
+		"<li>Santana: <a href=\"https://example.com/5\" target=\"_blank\">Song5</a>, 6.00</li>",
+		"<p>Eliminated: Jesus</p>",
+	} {
+		if !strings.Contains(string(html), expected) {
+			t.Errorf("Results don't contain %q", expected)
+		}
+	}
+	if strings.Index(string(html), "<h2>Santana</h2>") > strings.Index(string(html), "<h2>Jesus</h2>") {
+		t.Error("Winner isn't ranked first in the results")
+	}
+
+	// Every song played in the rounds is saved for the tournaments
+	content, err := os.ReadFile(strings.TrimSuffix(results[0], ".html") + ".json")
+	if err != nil {
+		t.Fatalf("Failed to read results JSON: %v", err)
+	}
+	var songs songResults
+	if err = json.Unmarshal(content, &songs); err != nil {
+		t.Fatalf("Failed to parse results JSON: %v", err)
+	}
+	if len(songs.Songs) != 5 {
+		t.Errorf("Results JSON has %d songs, want 5", len(songs.Songs))
+	}
+}
+
+func TestEliminationRepeatedTie(t *testing.T) {
+	logDir := t.TempDir()
+	p, bot := newScriptedGame(t, WithOutputDirectory(nil), WithElimination(true), WithEventLog(logDir))
+	// Santana and Jesus tie also in the first round
+	runScript(t, p, bot, eliminationRounds("10/10"))
+
+	sent := strings.Join(bot.receivedMessages, "\n")
+	if expected := "Santana, Jesus share the lowest score 6.00 and tie on the earlier rounds too, " +
+		"a draw breaks the tie"; !strings.Contains(sent, expected) {
+		t.Errorf("Messages don't contain %q", expected)
+	}
+	eliminated := slices.IndexFunc(bot.receivedMessages, func(m string) bool {
+		return strings.Contains(m, "Song5") && strings.Contains(m, "is eliminated") ||
+			strings.Contains(m, "Song6") && strings.Contains(m, "is eliminated")
+	})
+	if eliminated == -1 {
+		t.Fatalf("Nobody was eliminated on the tie:\n%s", sent)
+	}
+
+	// Replay draws the same panelist
+	logs, err := filepath.Glob(filepath.Join(logDir, "jukebox_jury_events_*.jsonl"))
+	if err != nil || len(logs) != 1 {
+		t.Fatalf("Expected a single event log, got %v: %v", logs, err)
+	}
+	fin, err := os.Open(logs[0])
+	if err != nil {
+		t.Fatalf("Failed to open event log: %v", err)
+	}
+	defer fin.Close()
+	events, err := ReadEvents(fin)
+	if err != nil {
+		t.Fatalf("Failed to read event log: %v", err)
+	}
+	var out bytes.Buffer
+	if _, err = Replay(events, telegram.NewFakeBot(&out), WithOutputDirectory(nil), withoutShuffle()); err != nil {
+		t.Fatalf("Replay diverged: %v", err)
+	}
+	if !strings.Contains(out.String(), bot.receivedMessages[eliminated]) {
+		t.Errorf("Replay didn't eliminate the same panelist, want %q:\n%s",
+			bot.receivedMessages[eliminated], out.String())
+	}
+}
@@ -0,0 +1,235 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"weezel/jukeboxjury/internal/logger"
+)
+
+var ErrEngineStopped = errors.New("engine stopped")
+
+// Scheduler calls fn after the duration d in the goroutine which owns the
+// game. The returned function cancels the call if it hasn't happened yet.
+type Scheduler func(d time.Duration, fn func()) (cancel func())
+
+// immediateScheduler calls fn right away. It's used when the game isn't
+// run by an Engine, e.g. in tests and replays, to keep the game deterministic.
+func immediateScheduler(_ time.Duration, fn func()) func() {
+	fn()
+	return func() {}
+}
+
+// Runner runs the work outside of the goroutine which owns the game, so
+// that slow work doesn't hold up the game. The function returned by the
+// work is called in the goroutine which owns the game.
+type Runner func(work func() (done func()))
+
+// immediateRunner runs the work right away, see immediateScheduler.
+func immediateRunner(work func() func()) {
+	work()()
+}
+
+// after calls fn after the duration d. Fired timers are logged so that
+// replays fire them at the same points of the game.
+func (p *Play) after(d time.Duration, fn func()) (cancel func()) {
+	return p.schedule(d, func() {
+		p.recordEvent(Event{Type: EventTimer})
+		fn()
+	})
+}
+
+// Snapshot is a read-only copy of the game state.
+type Snapshot struct {
+	StartedAt      time.Time  `json:"started_at"`
+	Host           string     `json:"host,omitempty"`
+	Panelists      []Panelist `json:"panelists"`
+	AudienceJurors []Panelist `json:"audience_jurors"`
+	State          State      `json:"state"`
+}
+
+// Snapshot copies the game state. Use Engine.Snapshot instead when the
+// game is run by an engine.
+func (p *Play) Snapshot() Snapshot {
+	snap := Snapshot{
+		StartedAt:      p.StartedAt,
+		State:          p.state,
+		Panelists:      copyPanelists(p.Panelists),
+		AudienceJurors: copyPanelists(p.AudienceJurors),
+	}
+	if p.host != nil {
+		snap.Host = p.host.Name
+	}
+	return snap
+}
+
+func copyPanelists(panelists []*Panelist) []Panelist {
+	copied := make([]Panelist, 0, len(panelists))
+	for _, panelist := range panelists {
+		pan := *panelist
+		if panelist.Song != nil {
+			song := *panelist.Song
+			pan.Song = &song
+		}
+		pan.ReceivedReviews = make([]*Review, 0, len(panelist.ReceivedReviews))
+		for _, review := range panelist.ReceivedReviews {
+			r := *review
+			pan.ReceivedReviews = append(pan.ReceivedReviews, &r)
+		}
+		copied = append(copied, pan)
+	}
+	return copied
+}
+
+// Engine owns the game and runs it in a single goroutine. Messages, timer
+// events and snapshot requests are passed to the goroutine over channels,
+// which makes the engine safe to use from several goroutines.
+type Engine struct {
+	play      *Play
+	messages  chan Message
+	calls     chan func() // Run in the engine's goroutine, e.g. fired timers
+	finished  chan func() // Results of the background work
+	snapshots chan chan Snapshot
+	done      chan struct{}
+	pending   int // Background work running, owned by the engine's goroutine
+}
+
+func NewEngine(play *Play) *Engine {
+	e := &Engine{
+		play:      play,
+		messages:  make(chan Message),
+		calls:     make(chan func()),
+		finished:  make(chan func()),
+		snapshots: make(chan chan Snapshot),
+		done:      make(chan struct{}),
+	}
+	play.schedule = e.schedule
+	play.background = e.background
+
+	return e
+}
+
+// Run processes the events until the context is cancelled. The event
+// being processed and the background work, such as the voice reviews being
+// transcribed, are always finished and the running game is shut down before
+// returning. Messages are sent as they are processed, so there is no queue
+// of outgoing messages left to flush.
+func (e *Engine) Run(ctx context.Context) error {
+	defer close(e.done)
+
+	logger.Logger.Info().Msg("Game engine started")
+	for {
+		select {
+		case <-ctx.Done():
+			if e.pending > 0 {
+				logger.Logger.Info().Msgf("Waiting for %d background jobs to finish", e.pending)
+			}
+			for ; e.pending > 0; e.pending-- {
+				(<-e.finished)()
+			}
+			e.play.Shutdown()
+			logger.Logger.Info().Msg("Game engine stopped")
+			return ctx.Err()
+		case msg := <-e.messages:
+			e.play.Handle(msg)
+		case fn := <-e.calls:
+			fn()
+		case fn := <-e.finished:
+			e.pending--
+			fn()
+		case reply := <-e.snapshots:
+			reply <- e.play.Snapshot()
+		}
+	}
+}
+
+// Submit passes the message to the game. It blocks until the engine
+// has taken the message, so it will be handled before any later request.
+func (e *Engine) Submit(ctx context.Context, msg Message) error {
+	select {
+	case <-e.done:
+		return ErrEngineStopped
+	default:
+	}
+
+	select {
+	case e.messages <- msg:
+		return nil
+	case <-e.done:
+		return ErrEngineStopped
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ExplainParseError tells the sender why the message couldn't be parsed,
+// see Play.ExplainParseError.
+func (e *Engine) ExplainParseError(ctx context.Context, chatID int64, err error) error {
+	select {
+	case e.calls <- func() { e.play.ExplainParseError(chatID, err) }:
+		return nil
+	case <-e.done:
+		return ErrEngineStopped
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Snapshot returns a copy of the game state.
+func (e *Engine) Snapshot(ctx context.Context) (Snapshot, error) {
+	reply := make(chan Snapshot, 1)
+	select {
+	case e.snapshots <- reply:
+	case <-e.done:
+		return Snapshot{}, ErrEngineStopped
+	case <-ctx.Done():
+		return Snapshot{}, ctx.Err()
+	}
+
+	return <-reply, nil
+}
+
+// schedule implements Scheduler by sending fn to the engine's goroutine
+// once the timer fires.
+func (e *Engine) schedule(d time.Duration, fn func()) func() {
+	cancelled := make(chan struct{})
+	timer := time.AfterFunc(d, func() {
+		select {
+		case e.calls <- func() {
+			// Timer might have been cancelled while waiting in the queue
+			select {
+			case <-cancelled:
+			default:
+				fn()
+			}
+		}:
+		case <-cancelled:
+		case <-e.done:
+		}
+	})
+
+	return func() {
+		timer.Stop()
+		select {
+		case <-cancelled:
+		default:
+			close(cancelled)
+		}
+	}
+}
+
+// background implements Runner by running the work in a goroutine of its
+// own and sending its result to the engine's goroutine. The engine waits
+// for the work when stopping, the result is dropped only if the engine
+// stopped without waiting, e.g. after a panic.
+func (e *Engine) background(work func() func()) {
+	e.pending++
+	go func() {
+		done := work()
+		select {
+		case e.finished <- done:
+		case <-e.done:
+		}
+	}()
+}
@@ -0,0 +1,89 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestEngine(t *testing.T) {
+	mockBot := mockTelegramBot{
+		mSend: func(_ tgbotapi.Chattable) (tgbotapi.Message, error) {
+			return tgbotapi.Message{}, nil
+		},
+		receivedMessages: []string{},
+	}
+	engine := NewEngine(New(&mockBot, 1, WithOutputDirectory(nil)))
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error)
+	go func() { runErr <- engine.Run(ctx) }()
+
+	if err := engine.Submit(ctx, Message{Command: CommandStart, PlayerName: "Santana", FromID: 666}); err != nil {
+		t.Fatalf("Submit() failed: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			msg := Message{
+				Command:    CommandJoin,
+				PlayerName: fmt.Sprintf("Panelist%d", i),
+				FromID:     int64(i + 1),
+			}
+			if err := engine.Submit(ctx, msg); err != nil {
+				t.Errorf("Submit() failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	snap, err := engine.Snapshot(ctx)
+	if err != nil {
+		t.Fatalf("Snapshot() failed: %v", err)
+	}
+	if snap.State != StateWaitPanelistsToJoin {
+		t.Errorf("Snapshot state = %s, want %s", snap.State, StateWaitPanelistsToJoin)
+	}
+	if len(snap.Panelists) != 11 {
+		t.Errorf("Snapshot has %d panelists, want 11", len(snap.Panelists))
+	}
+
+	// Modifying the snapshot must not affect the game
+	snap.Panelists[0].Song.URL = "https://example.com/modified"
+	again, err := engine.Snapshot(ctx)
+	if err != nil {
+		t.Fatalf("Snapshot() failed: %v", err)
+	}
+	if again.Panelists[0].Song.URL != "" {
+		t.Errorf("Snapshot shares the song with the game")
+	}
+
+	fired := make(chan struct{})
+	engine.schedule(time.Millisecond, func() { close(fired) })
+	cancelled := engine.schedule(time.Millisecond, func() { t.Errorf("Cancelled timer fired") })
+	cancelled()
+	select {
+	case <-fired:
+	case <-time.After(time.Second):
+		t.Errorf("Timer didn't fire")
+	}
+
+	cancel()
+	if err = <-runErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() = %v, want %v", err, context.Canceled)
+	}
+	if !slices.Contains(mockBot.receivedMessages, "The bot is going down, the game was interrupted") {
+		t.Errorf("Running game wasn't interrupted on shutdown")
+	}
+	if err = engine.Submit(context.Background(), Message{}); !errors.Is(err, ErrEngineStopped) {
+		t.Errorf("Submit() after stop = %v, want %v", err, ErrEngineStopped)
+	}
+}
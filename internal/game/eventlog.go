@@ -0,0 +1,401 @@
+package game
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"weezel/jukeboxjury/internal/integration/telegram"
+	"weezel/jukeboxjury/internal/logger"
+	"weezel/jukeboxjury/internal/tournament"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+type EventType string
+
+const (
+	// EventMessage is an inbound message from a user.
+	EventMessage EventType = "message"
+	// EventTransition is a state transition.
+	EventTransition EventType = "transition"
+	// EventSeed is the seed of the random generator used in the game.
+	EventSeed EventType = "seed"
+	// EventPoll is the final result of an audience poll.
+	EventPoll EventType = "poll"
+	// EventChatAdmins are the chat administrators fetched from Telegram.
+	EventChatAdmins EventType = "chat_admins"
+	// EventShutdown is logged when the bot went down in the middle of the game.
+	EventShutdown EventType = "shutdown"
+	// EventTimer is a timer of the game firing, such as the next review
+	// of a paced reveal.
+	EventTimer EventType = "timer"
+	// EventTranscript is the transcript of a voice review, logged with the
+	// review once the transcript is ready.
+	EventTranscript EventType = "transcript"
+	// EventBracket is the tournament bracket a tournament night began with.
+	EventBracket EventType = "bracket"
+	// EventOptions are the options the game was played with.
+	EventOptions EventType = "options"
+)
+
+// Event is a single line in the game's event log. Besides the messages and
+// transitions, all the inputs which don't come from messages are logged so
+// the game can be replayed deterministically.
+type Event struct {
+	Time       time.Time           `json:"time"`
+	Message    *Message            `json:"message,omitempty"`
+	Poll       *tgbotapi.Poll      `json:"poll,omitempty"`
+	Bracket    *tournament.Bracket `json:"bracket,omitempty"`
+	Options    *Options            `json:"options,omitempty"`
+	Type       EventType           `json:"type"`
+	From       string              `json:"from,omitempty"`
+	To         string              `json:"to,omitempty"`
+	Transcript string              `json:"transcript,omitempty"`
+	ChatAdmins []int64             `json:"chat_admins,omitempty"`
+	Seed       uint64              `json:"seed,omitempty"`
+}
+
+// Options are the options which change how the game plays. They are logged
+// when the event log is opened so that the game is replayed the same way.
+type Options struct {
+	Permissions      map[Action]Role     `json:"permissions"`
+	Aliases          map[string][]string `json:"aliases,omitempty"`
+	Language         Language            `json:"language"`
+	Prefix           string              `json:"prefix"`
+	BotAdmins        []int64             `json:"bot_admins,omitempty"`
+	RevealDelay      time.Duration       `json:"reveal_delay"`
+	RatingMax        int                 `json:"rating_max"`
+	AudienceMode     AudienceMode        `json:"audience_mode"`
+	RevealStyle      RevealStyle         `json:"reveal_style"`
+	JudgeScoring     JudgeScoring        `json:"judge_scoring"`
+	AudiencePoll     bool                `json:"audience_poll"`
+	Scoreboard       bool                `json:"scoreboard"`
+	HostCommentary   bool                `json:"host_commentary"`
+	ScorePredictions bool                `json:"score_predictions"`
+	Tournaments      bool                `json:"tournaments"`
+	Elimination      bool                `json:"elimination"`
+	Teams            bool                `json:"teams"`
+}
+
+// options returns the options the game is played with.
+func (p *Play) options() Options {
+	return Options{
+		Permissions:      maps.Clone(p.permissions),
+		Aliases:          maps.Clone(p.customAliases),
+		Language:         p.language,
+		Prefix:           p.prefix,
+		BotAdmins:        slices.Clone(p.botAdmins),
+		RevealDelay:      p.revealDelay,
+		RatingMax:        p.ratingMax,
+		AudienceMode:     p.audienceMode,
+		RevealStyle:      p.revealStyle,
+		JudgeScoring:     p.judgeScoring,
+		AudiencePoll:     p.audiencePoll,
+		Scoreboard:       p.scoreboard,
+		HostCommentary:   p.hostCommentary,
+		ScorePredictions: p.scorePredictions,
+		Tournaments:      p.tournaments,
+		Elimination:      p.elimination,
+		Teams:            p.teams,
+	}
+}
+
+// withOptions plays the game with the logged options.
+func withOptions(o Options) PlayOption {
+	return func(p *Play) {
+		if o.Permissions != nil {
+			p.permissions = maps.Clone(o.Permissions)
+		}
+		p.customAliases = maps.Clone(o.Aliases)
+		p.language = o.Language
+		p.prefix = o.Prefix
+		p.botAdmins = slices.Clone(o.BotAdmins)
+		p.revealDelay = o.RevealDelay
+		p.ratingMax = o.RatingMax
+		p.audienceMode = o.AudienceMode
+		p.revealStyle = o.RevealStyle
+		p.judgeScoring = o.JudgeScoring
+		p.audiencePoll = o.AudiencePoll
+		p.scoreboard = o.Scoreboard
+		p.hostCommentary = o.HostCommentary
+		p.scorePredictions = o.ScorePredictions
+		p.tournaments = o.Tournaments
+		p.elimination = o.Elimination
+		p.teams = o.Teams
+	}
+}
+
+// WithEventLog writes an event log of each game as JSON lines
+// into the given directory.
+func WithEventLog(directory string) PlayOption {
+	return func(p *Play) {
+		p.eventLogDirectory = directory
+	}
+}
+
+// WithClock overrides the clock used for timestamps.
+func WithClock(now func() time.Time) PlayOption {
+	return func(p *Play) {
+		p.now = now
+	}
+}
+
+// withScheduler overrides the scheduler of the timers.
+func withScheduler(schedule Scheduler) PlayOption {
+	return func(p *Play) {
+		p.schedule = schedule
+	}
+}
+
+// withRunner overrides how the work is run in the background.
+func withRunner(run Runner) PlayOption {
+	return func(p *Play) {
+		p.background = run
+	}
+}
+
+// withoutShuffle keeps the hosts in the order the panelists joined,
+// so that tests can script the games.
+func withoutShuffle() PlayOption {
+	return func(p *Play) {
+		p.keepOrder = true
+	}
+}
+
+// withTransitionObserver calls the hook after every transition.
+func withTransitionObserver(hook Hook) PlayOption {
+	return func(p *Play) {
+		for state := range stateNames {
+			p.onEnter[state] = append(p.onEnter[state], hook)
+		}
+	}
+}
+
+// openEventLog creates the event log file for a new game.
+func (p *Play) openEventLog() {
+	if p.eventLogDirectory == "" || p.eventLog != nil {
+		return
+	}
+
+	fname := fmt.Sprintf("jukebox_jury_events_%s.jsonl", p.now().Format("2006-01-02T150405"))
+	fout, err := os.OpenFile(
+		filepath.Join(p.eventLogDirectory, fname),
+		os.O_CREATE|os.O_WRONLY|os.O_APPEND,
+		0o600,
+	)
+	if err != nil {
+		logger.Logger.Error().Err(err).Str("filename", fname).Msg("Failed to create event log")
+		return
+	}
+	p.eventLog = fout
+	logger.Logger.Info().Str("filename", fout.Name()).Msg("Event log created")
+
+	options := p.options()
+	p.recordEvent(Event{Type: EventOptions, Options: &options})
+}
+
+// closeEventLog closes the event log of the ended game.
+func (p *Play) closeEventLog() {
+	if p.eventLog == nil {
+		return
+	}
+	if err := p.eventLog.Close(); err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to close event log")
+	}
+	p.eventLog = nil
+}
+
+// recordEvent appends the event to the event log, if one is open.
+func (p *Play) recordEvent(ev Event) {
+	if p.eventLog == nil {
+		return
+	}
+
+	ev.Time = p.now()
+	line, err := json.Marshal(ev)
+	if err != nil {
+		logger.Logger.Error().Err(err).Interface("event", ev).Msg("Failed to marshal event")
+		return
+	}
+	if _, err = p.eventLog.Write(append(line, '\n')); err != nil {
+		logger.Logger.Error().Err(err).Interface("event", ev).Msg("Failed to write event")
+	}
+}
+
+// seedRandom resets the random generator used for shuffling.
+func (p *Play) seedRandom(seed uint64) {
+	//nolint:gosec // Shuffling songs doesn't need a secure generator
+	p.rng = rand.New(rand.NewPCG(seed, seed))
+	p.recordEvent(Event{Type: EventSeed, Seed: seed})
+}
+
+// validate checks that the event carries the payload of its type.
+func (ev Event) validate() error {
+	var missing string
+	switch ev.Type {
+	case EventMessage, EventTranscript:
+		if ev.Message == nil {
+			missing = "message"
+		}
+	case EventPoll:
+		if ev.Poll == nil {
+			missing = "poll"
+		}
+	case EventBracket:
+		if ev.Bracket == nil {
+			missing = "bracket"
+		}
+	case EventOptions:
+		if ev.Options == nil {
+			missing = "options"
+		}
+	case EventTransition, EventSeed, EventChatAdmins, EventShutdown, EventTimer:
+	}
+	if missing != "" {
+		return fmt.Errorf("%s event has no %s", ev.Type, missing)
+	}
+	return nil
+}
+
+// ReadEvents parses an event log written by a game.
+func ReadEvents(r io.Reader) ([]Event, error) {
+	events := []Event{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var ev Event
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			return nil, fmt.Errorf("event log line %d: %w", lineNum, err)
+		}
+		if err := ev.validate(); err != nil {
+			return nil, fmt.Errorf("event log line %d: %w", lineNum, err)
+		}
+		events = append(events, ev)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read event log: %w", err)
+	}
+
+	return events, nil
+}
+
+// Replay rebuilds the game by feeding the logged messages into a new game
+// which uses the fake bot as its transport. The game is played with the
+// logged options, which the given options override. Logged inputs which
+// don't come from messages, such as poll results, are served by the fake
+// bot, the transcripts by the transcriber and the brackets by the bracket
+// loader. Transitions which differ from the logged ones are returned as an
+// error, and events missing their payload fail the replay before it starts.
+func Replay(events []Event, bot *telegram.FakeBot, opts ...PlayOption) (*Play, error) {
+	var now time.Time
+	var brackets []*tournament.Bracket
+	var logged []PlayOption
+	for i, ev := range events {
+		if err := ev.validate(); err != nil {
+			return nil, fmt.Errorf("event %d: %w", i+1, err)
+		}
+		switch ev.Type {
+		case EventOptions:
+			logged = append(logged, withOptions(*ev.Options))
+		case EventPoll:
+			bot.Polls = append(bot.Polls, *ev.Poll)
+		case EventChatAdmins:
+			for _, uid := range ev.ChatAdmins {
+				bot.ChatAdmins = append(bot.ChatAdmins, tgbotapi.ChatMember{
+					User:   &tgbotapi.User{ID: uid},
+					Status: "administrator",
+				})
+			}
+		case EventBracket:
+			brackets = append(brackets, ev.Bracket)
+		case EventMessage, EventTransition, EventSeed, EventShutdown, EventTimer, EventTranscript:
+		}
+	}
+
+	var transitions []Event
+	var timers []*func()
+	opts = append(append(logged, opts...),
+		WithClock(func() time.Time { return now }),
+		// Timers fire when their logged events are reached
+		withScheduler(func(_ time.Duration, fn func()) func() {
+			timer := &fn
+			timers = append(timers, timer)
+			return func() { *timer = nil }
+		}),
+		// Voice notes aren't available, the voice reviews are finished with
+		// the logged transcripts when they are reached
+		withRunner(func(func() func()) {}),
+		// Tournament nights begin with the logged brackets
+		withBracketLoader(func() (*tournament.Bracket, error) {
+			if len(brackets) == 0 {
+				return nil, errors.New("no logged bracket left")
+			}
+			bracket := brackets[0]
+			brackets = brackets[1:]
+			return bracket, nil
+		}),
+		withTransitionObserver(func(from State, to State) {
+			transitions = append(transitions, Event{
+				Type: EventTransition,
+				From: from.String(),
+				To:   to.String(),
+			})
+		}),
+	)
+	p := New(bot, 0, opts...)
+
+	var errs []error
+	expected := 0
+	for _, ev := range events {
+		now = ev.Time
+		switch ev.Type {
+		case EventMessage:
+			p.Handle(*ev.Message)
+		case EventSeed:
+			p.seedRandom(ev.Seed)
+		case EventTransition:
+			if expected >= len(transitions) {
+				errs = append(errs,
+					fmt.Errorf("logged transition %s -> %s didn't happen", ev.From, ev.To))
+				continue
+			}
+			got := transitions[expected]
+			if got.From != ev.From || got.To != ev.To {
+				errs = append(errs, fmt.Errorf("logged transition %s -> %s, replayed %s -> %s",
+					ev.From, ev.To, got.From, got.To))
+			}
+			expected++
+		case EventShutdown:
+			p.Shutdown()
+		case EventTimer:
+			timers = slices.DeleteFunc(timers, func(timer *func()) bool { return *timer == nil })
+			if len(timers) == 0 {
+				errs = append(errs, errors.New("logged timer didn't fire"))
+				continue
+			}
+			fire := *timers[0]
+			timers = timers[1:]
+			fire()
+		case EventTranscript:
+			p.finishVoiceReview(*ev.Message, ev.Transcript, "")
+		case EventPoll, EventChatAdmins, EventBracket, EventOptions:
+		}
+	}
+	for _, got := range transitions[min(expected, len(transitions)):] {
+		errs = append(errs, fmt.Errorf("replayed transition %s -> %s wasn't logged", got.From, got.To))
+	}
+
+	return p, errors.Join(errs...)
+}
@@ -0,0 +1,120 @@
+package game
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"weezel/jukeboxjury/internal/integration/telegram"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+var chatPrefix = regexp.MustCompile(`^\[chat -?[0-9]+\] `)
+
+func TestEventLogReplay(t *testing.T) {
+	logDir := t.TempDir()
+	mockBot := mockTelegramBot{
+		mSend: func(_ tgbotapi.Chattable) (tgbotapi.Message, error) {
+			return tgbotapi.Message{}, nil
+		},
+		receivedMessages: []string{},
+	}
+
+	santana := &tgbotapi.User{ID: 666, UserName: "Santana"}
+	jesus := &tgbotapi.User{ID: 123, UserName: "Jesus"}
+	pjotr := &tgbotapi.User{ID: 7, UserName: "Pjotr"}
+	updates := []tgbotapi.Message{
+		testMessage(santana, "levyraati aloita"),
+		testMessage(jesus, "levyraati liity"),
+		testMessage(pjotr, "levyraati liity"),
+		testMessage(santana, "levyraati jatka"),
+		testMessage(santana, "levyraati esitä Song1 https://example.com/1"),
+		testMessage(jesus, "levyraati esitä Song2 https://example.com/2"),
+		testMessage(pjotr, "levyraati esitä Song3 https://example.com/3"),
+		testMessage(jesus, "levyraati arvioi Review 8/10"),
+		testMessage(pjotr, "levyraati arvioi Review 8/10"),
+		testMessage(santana, "levyraati arvioi Review 2/10"),
+		testMessage(jesus, "levyraati arvioi Review 4/10"),
+		testMessage(pjotr, "levyraati arvioi Review 6/10"),
+		testMessage(santana, "levyraati arvioi Review 6/10"),
+	}
+
+	// Panelists are shuffled, so some of the reviews hit the reviewer's own
+	// song. Replay must reproduce the same order from the logged seed, and
+	// speak the logged language.
+	p := New(&mockBot, 1, WithOutputDirectory(nil), WithEventLog(logDir), WithLanguage(LanguageFinnish))
+	for i, update := range updates {
+		msg, err := ParseToMessage(tgbotapi.Update{Message: &update})
+		if err != nil {
+			t.Fatalf("Failed to parse %d %q: %#v", i, update.Text, err)
+		}
+		p.Handle(msg)
+	}
+
+	logs, err := filepath.Glob(filepath.Join(logDir, "jukebox_jury_events_*.jsonl"))
+	if err != nil || len(logs) != 1 {
+		t.Fatalf("Expected a single event log, got %v: %v", logs, err)
+	}
+	fin, err := os.Open(logs[0])
+	if err != nil {
+		t.Fatalf("Failed to open event log: %v", err)
+	}
+	defer fin.Close()
+
+	events, err := ReadEvents(fin)
+	if err != nil {
+		t.Fatalf("Failed to read event log: %v", err)
+	}
+	if len(events) < len(updates) {
+		t.Fatalf("Expected at least %d events, got %d", len(updates), len(events))
+	}
+
+	var out bytes.Buffer
+	if _, err = Replay(events, telegram.NewFakeBot(&out), WithOutputDirectory(nil)); err != nil {
+		t.Fatalf("Replay diverged: %v", err)
+	}
+
+	replayed := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		replayed = append(replayed, chatPrefix.ReplaceAllString(line, ""))
+	}
+	if strings.Join(replayed, "\n") != strings.Join(mockBot.receivedMessages, "\n") {
+		t.Errorf("Replayed messages differ, got:\n%s\nwant:\n%s",
+			strings.Join(replayed, "\n"), strings.Join(mockBot.receivedMessages, "\n"))
+	}
+}
+
+func TestEventsMissingPayload(t *testing.T) {
+	tests := []struct {
+		line    string
+		wantErr string
+	}{
+		{line: `{"type":"message"}`, wantErr: "event log line 2: message event has no message"},
+		{
+			line:    `{"type":"transcript","transcript":"Nice"}`,
+			wantErr: "event log line 2: transcript event has no message",
+		},
+		{line: `{"type":"poll"}`, wantErr: "event log line 2: poll event has no poll"},
+		{line: `{"type":"bracket"}`, wantErr: "event log line 2: bracket event has no bracket"},
+		{line: `{"type":"options"}`, wantErr: "event log line 2: options event has no options"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			log := `{"type":"seed","seed":1}` + "\n" + tt.line + "\n"
+			if _, err := ReadEvents(strings.NewReader(log)); err == nil || err.Error() != tt.wantErr {
+				t.Errorf("ReadEvents() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Events built in code are checked too
+	_, err := Replay([]Event{{Type: EventSeed, Seed: 1}, {Type: EventMessage}}, telegram.NewFakeBot(io.Discard))
+	if err == nil || err.Error() != "event 2: message event has no message" {
+		t.Errorf("Replay() error = %v, want missing message", err)
+	}
+}
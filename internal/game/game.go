@@ -3,19 +3,18 @@ package game
 import (
 	"errors"
 	"fmt"
+	"io"
 	"math/rand/v2"
 	"net/url"
 	"os"
 	"path/filepath"
-	"regexp"
 	"slices"
 	"strings"
 	"time"
 
 	"weezel/jukeboxjury/internal/integration/telegram"
 	"weezel/jukeboxjury/internal/logger"
-
-	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"weezel/jukeboxjury/internal/tournament"
 )
 
 const JukeboxJuryPrefix = "levyraati"
@@ -25,20 +24,34 @@ const (
 	CommandStop     = "lopeta"
 	CommandContinue = "jatka"
 	CommandJoin     = "liity"
-	CommandPresent  = "(esitä|esitys)"
-	CommandReview   = "(arvio|arvioi|arvostele)"
+	CommandKick     = "potki"
+	CommandSkip     = "ohita"
+	CommandHelp     = "apua"
+	CommandStatus   = "tila"
+	CommandPresent  = "esitä"
+	CommandReview   = "arvioi"
+	CommandComment  = "kommentoi"
+	CommandTour     = "turnaus"
+	CommandVote     = "äänestä"
 )
 
 var (
-	ErrInvalidPrefix = errors.New("invalid prefix")
 	ErrInvaliSyntax  = errors.New("invalid syntax")
+	ErrInvalidPrefix = fmt.Errorf("%w: invalid prefix", ErrInvaliSyntax)
 )
 
 var AllCommands = []string{
 	CommandContinue,
 	CommandJoin,
+	CommandKick,
+	CommandSkip,
+	CommandHelp,
+	CommandStatus,
 	CommandPresent,
 	CommandReview,
+	CommandComment,
+	CommandTour,
+	CommandVote,
 }
 
 type PlayOption func(*Play)
@@ -49,13 +62,31 @@ func WithOutputDirectory(fpath *string) PlayOption {
 	}
 }
 
-func WithResultsURL(resultsURL string) PlayOption {
-	resURL, err := url.Parse(resultsURL)
-	if err != nil {
-		logger.Logger.Fatal().Err(err).Msgf("Failed to parse results URL %q", resultsURL)
+func WithResultsURL(resultsURL *url.URL) PlayOption {
+	return func(p *Play) {
+		p.resultsURL = resultsURL
 	}
+}
+
+// WithRatingScale sets the highest rating of a review, the lowest is zero.
+func WithRatingScale(maxRating int) PlayOption {
 	return func(p *Play) {
-		p.resultsURL = resURL
+		p.ratingMax = maxRating
+	}
+}
+
+// WithTimeZone sets the time zone of the timestamps in the results.
+func WithTimeZone(loc *time.Location) PlayOption {
+	return func(p *Play) {
+		p.timeZone = loc
+	}
+}
+
+// WithTimeFormat sets the layout of the timestamps in the results,
+// see time.Layout.
+func WithTimeFormat(layout string) PlayOption {
+	return func(p *Play) {
+		p.timeFormat = layout
 	}
 }
 
@@ -63,14 +94,61 @@ func WithResultsURL(resultsURL string) PlayOption {
 type Play struct {
 	host              *Panelist
 	StartedAt         time.Time
+	EndedAt           time.Time
 	bot               telegram.Boter
 	resultsDirectory  *string
+	eventLogDirectory string
+	timeFormat        string
+	scoreboardText    richText
+	prefix            string
 	resultsURL        *url.URL
+	timeZone          *time.Location
+	eventLog          *os.File
+	rng               *rand.Rand
+	reveal            *pacedReveal
+	bracket           *tournament.Bracket
+	match             *tournament.Match
+	loadBracket       func() (*tournament.Bracket, error)
+	now               func() time.Time
+	schedule          Scheduler
+	background        Runner
+	transcriber       Transcriber
+	permissions       map[Action]Role
 	Panelists         []*Panelist
+	AudienceJurors    []*Panelist
+	EliminationRounds []EliminationRound
+	botAdmins         []int64
 	gameStarterUID    int64
 	chatID            int64
+	revealDelay       time.Duration
+	transcribeTimeout time.Duration
+	commands          map[string]string
+	customAliases     map[string][]string
+	sharedSongs       map[int64]Message // Waiting for confirmation, by the sharer
+	voiceReviews      map[int64]*Song   // Reviewed songs waiting for the transcript, by the reviewer
+	votes             map[int64]int     // Song slots of the match, by the voter
+	language          Language
+	ratingMax         int
+	onEnter           map[State][]Hook
+	onExit            map[State][]Hook
+	audienceMode      AudienceMode
+	revealStyle       RevealStyle
+	judgeScoring      JudgeScoring
+	state             State
+	pollMessageID     int
+	matchRound        int
+	scoreboardID      int
+	audiencePoll      bool
+	scoreboard        bool
 	allSongsSubmitted bool
-	gameActive        bool
+	interrupted       bool
+	hostCommentary    bool
+	scorePredictions  bool
+	tournaments       bool
+	elimination       bool
+	teams             bool
+	keepOrder         bool // Hosts follow the joining order instead of a shuffle
+	running           bool // Handling a message or a timer
 }
 
 func New(bot telegram.Boter, chatID int64, opts ...PlayOption) *Play {
@@ -85,117 +163,235 @@ func New(bot telegram.Boter, chatID int64, opts ...PlayOption) *Play {
 		chatID:           chatID,
 		resultsDirectory: &homeDir,
 		resultsURL:       resultsURL,
+		timeZone:         timeZone,
+		timeFormat:       DefaultTimeFormat,
+		ratingMax:        10,
+		revealDelay:      DefaultRevealDelay,
+		prefix:           JukeboxJuryPrefix,
+		language:         LanguageEnglish,
+		permissions:      defaultPermissions(),
 		Panelists:        []*Panelist{},
-	}
+		AudienceJurors:   []*Panelist{},
+		sharedSongs:      map[int64]Message{},
+		voiceReviews:     map[int64]*Song{},
+		onEnter:          map[State][]Hook{},
+		onExit:           map[State][]Hook{},
+		now:              time.Now,
+		schedule:         immediateScheduler,
+		background:       immediateRunner,
+		//nolint:gosec // Shuffling songs doesn't need a secure generator
+		rng: rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())),
+	}
+	g.onEnter[StateInit] = []Hook{func(_ State, _ State) { g.ClearGame() }}
+	g.loadBracket = g.seasonBracket
 
 	// Override defaults with given options
 	for _, opt := range opts {
 		opt(g)
 	}
+	g.commands = commandsFor(g.language, g.customAliases)
 
 	return g
 }
 
+// handleGlobalCommand handles the commands which are valid in every state.
+// Returns true when the message was consumed.
+func (p *Play) handleGlobalCommand(msg Message) bool {
+	switch msg.Command {
+	case CommandHelp:
+		p.Help(msg)
+	case CommandStatus:
+		p.Status(msg)
+	case CommandStop:
+		if p.state == StateInit {
+			p.sendMessageToPanelist(msg.ChatID, p.tr(msgNoGameToStop))
+			return true
+		}
+		if !p.Authorize(msg, ActionStop) {
+			return true
+		}
+		logger.Logger.Info().Msgf("Panelist %s with ID %d stopped the game", msg.PlayerName, msg.FromID)
+		p.sendMessageToChannel(p.tr(msgGameStopped, bold(msg.PlayerName)))
+		if err := p.transition(StateInit); err != nil {
+			logger.Logger.Error().Err(err).Msg("Couldn't stop the game")
+		}
+	case commandShareSong, commandConfirmSong, commandCancelSong:
+		if p.state == StateAddSong {
+			return false
+		}
+		// Links shared outside of adding the songs are just chatter
+		if msg.Command != commandShareSong {
+			p.answerCallback(msg, p.tr(msgNothingToConfirm))
+		}
+	default:
+		return false
+	}
+
+	return true
+}
+
 // States
-func (p *Play) StartGame(msg Message) StateFunc {
+func (p *Play) init(msg Message) State {
+	if msg.Command == CommandTour && p.tournaments {
+		return StateStartTournament
+	}
+	if msg.Command != CommandStart {
+		p.sendMessageToPanelist(msg.ChatID,
+			p.tr(msgNoGameRunning, p.prefix, p.commandName(CommandStart)),
+		)
+		return StateInit
+	}
+
+	return StateStartGame
+}
+
+func (p *Play) startGame(msg Message) State {
 	logger.Logger.Debug().Msg("State: Game is starting")
 
-	// Game is about to be started, show intro
-	if p.StartedAt.IsZero() {
-		p.gameActive = true
-		p.StartedAt = time.Now().Local()
-		p.gameStarterUID = msg.FromID
-		p.sendMessageToChannel(
-			fmt.Sprintf("User %s started a new game, join by using command: %s %s",
-				msg.PlayerName, JukeboxJuryPrefix, CommandJoin,
-			),
-		)
-		logger.Logger.Info().
-			Str("game_starter_name", msg.PlayerName).
-			Int64("game_starter_id", msg.FromID).
-			Time("game_starter_at", p.StartedAt).
-			Msg("Game started")
-		p.addPanelist(msg)
+	p.StartedAt = p.now().Local()
+	p.gameStarterUID = msg.FromID
+	p.seedRandom(rand.Uint64())
+	started := msgGameStarted
+	if p.teams {
+		started = msgGameStartedTeams
 	}
+	p.sendMessageToChannel(
+		p.tr(started, bold(msg.PlayerName), p.prefix, p.commandName(CommandJoin)),
+	)
+	logger.Logger.Info().
+		Str("game_starter_name", msg.PlayerName).
+		Int64("game_starter_id", msg.FromID).
+		Time("game_starter_at", p.StartedAt).
+		Msg("Game started")
+	p.addPanelist(msg)
 
-	return p.WaitPanelistsToJoin
+	return StateWaitPanelistsToJoin
 }
 
-func (p *Play) WaitPanelistsToJoin(msg Message) StateFunc {
+func (p *Play) waitPanelistsToJoin(msg Message) State {
 	logger.Logger.Debug().Msg("State: Waiting panelists to join")
 
 	switch msg.Command {
 	case CommandJoin:
-		if ok := p.addPanelist(msg); !ok {
-			p.sendMessageToPanelist(msg.ChatID, "You are already in the game")
-		} else {
-			p.sendMessageToChannel(fmt.Sprintf("User %s joined the game", msg.PlayerName))
+		switch {
+		case p.addPanelist(msg):
+			p.announceJoined(p.Panelists[len(p.Panelists)-1])
+		case !p.switchTeam(msg):
+			p.sendMessageToPanelist(msg.ChatID, p.tr(msgAlreadyInGame))
+		}
+	case CommandKick:
+		if p.Authorize(msg, ActionKick) && !p.kickPanelist(msg) {
+			return StateInit
 		}
+	case CommandSkip:
+		p.sendMessageToPanelist(msg.ChatID, p.tr(msgNoSongToSkip))
 	case CommandContinue:
-		// TODO Add higher bit for the user who started the game?
+		if !p.Authorize(msg, ActionContinue) {
+			return StateWaitPanelistsToJoin
+		}
+		if p.singleTeam() {
+			p.sendMessageToChannel(p.tr(msgSingleTeam))
+			return StateWaitPanelistsToJoin
+		}
 		logger.Logger.Info().Msg("Panelists are ready, continuing")
-		p.sendMessageToChannel(fmt.Sprintf("User %s wants to proceed, continuing...", msg.PlayerName))
-		p.sendMessageToChannel(
-			fmt.Sprintf(
-				"Add song with the following command and format in private chat with the bot: %s %s "+
-					"description here https://link-as-last-item",
-				JukeboxJuryPrefix,
-				CommandPresent,
-			),
-		)
+		p.sendMessageToChannel(p.tr(msgContinuing, bold(msg.PlayerName)))
+		p.sendMessageToChannel(p.tr(msgHowToAddSong, p.prefix, p.commandName(CommandPresent)))
+		if p.scorePredictions {
+			p.sendMessageToChannel(
+				p.tr(msgHowToPredict, p.prefix, p.commandName(CommandPresent), p.ratingMax),
+			)
+		}
 		p.sendMessageToChannel(
-			fmt.Sprintf(
-				"Add review similar way (max score is 10, only integers): %s %s description here 0/10",
-				JukeboxJuryPrefix,
-				CommandReview,
-			),
+			p.tr(msgHowToReview, p.prefix, p.commandName(CommandReview), p.ratingMax),
 		)
-		return p.AddSong
+		return StateAddSong
 	}
 
-	return p.WaitPanelistsToJoin
+	return StateWaitPanelistsToJoin
 }
 
-var songCommandMatcher = regexp.MustCompile(CommandPresent)
-
-func (p *Play) AddSong(msg Message) StateFunc {
+func (p *Play) addSongs(msg Message) State {
 	logger.Logger.Debug().Msg("State: Add song")
 
-	if !songCommandMatcher.MatchString(msg.Command) {
+	switch msg.Command {
+	case CommandKick:
+		if !p.Authorize(msg, ActionKick) {
+			return StateAddSong
+		}
+		if !p.kickPanelist(msg) {
+			return StateInit
+		}
+		if !p.isAllSongsSubmitted() {
+			return StateAddSong
+		}
+		return StateShuffleHost
+	case CommandSkip:
+		p.sendMessageToPanelist(msg.ChatID, p.tr(msgNoSongToSkip))
+		return StateAddSong
+	case CommandJoin:
+		p.addAudienceJuror(msg)
+		return StateAddSong
+	case commandShareSong:
+		p.offerSharedSong(msg)
+		return StateAddSong
+	case commandCancelSong:
+		p.cancelSharedSong(msg)
+		return StateAddSong
+	case commandConfirmSong:
+		shared, confirmed := p.confirmSharedSong(msg)
+		if !confirmed {
+			return StateAddSong
+		}
+		msg = shared
+	}
+
+	if msg.Command != CommandPresent {
 		logger.Logger.Warn().Interface("msg", msg).Msg("Not a command")
-		p.sendMessageToPanelist(msg.ChatID, "Aww cute, but it's a wrong command.")
-		return p.AddSong
+		p.sendMessageToPanelist(msg.ChatID, p.tr(msgWrongCommand))
+		return StateAddSong
 	}
 
 	if err := p.addSong(msg); err != nil {
 		var songErr SongError
 		if errors.As(err, &songErr) {
-			p.sendMessageToPanelist(msg.ChatID, songErr.ErrForUser)
+			p.sendMessageToPanelist(msg.ChatID, p.tr(songErr.ErrForUser))
 		} else {
 			logger.Logger.Error().Err(err).Interface("msg", msg).Msg("Couldn't add song")
-			p.sendMessageToPanelist(msg.ChatID, "Me confused. Que pasa¿")
+			p.sendMessageToPanelist(msg.ChatID, p.tr(msgConfused))
 		}
-		return p.AddSong
+		return StateAddSong
 	}
 	logger.Logger.Info().
 		Interface("msg", msg).
 		Msgf("Panelist %s with ID %d added a song", msg.PlayerName, msg.FromID)
-	p.sendMessageToChannel(fmt.Sprintf("Panelist %s added a song", msg.PlayerName))
+	p.announceProgress(p.tr(msgSongAdded, bold(msg.PlayerName)))
 
 	if !p.allSongsSubmitted {
-		return p.AddSong
+		return StateAddSong
 	}
 
+	return StateShuffleHost
+}
+
+func (p *Play) shuffleHost(_ Message) State {
 	logger.Logger.Info().Msg("All songs submitted, continuing")
-	p.sendMessageToChannel("All songs submitted, continuing...")
+	p.sendMessageToChannel(p.tr(msgAllSongsSubmitted))
 
-	p.shuffleHost()
+	if p.keepOrder {
+		return StateIntroduceSong
+	}
+
+	logger.Logger.Debug().Msg("State: Shuffle the host")
 
-	return p.IntroduceSong(msg)
+	p.rng.Shuffle(len(p.Panelists), func(i, j int) {
+		p.Panelists[i], p.Panelists[j] = p.Panelists[j], p.Panelists[i]
+	})
+
+	return StateIntroduceSong
 }
 
-// IntroduceSong reveals the next song for the audience.
-func (p *Play) IntroduceSong(_ Message) StateFunc {
+// introduceSong reveals the next song for the audience.
+func (p *Play) introduceSong(_ Message) State {
 	logger.Logger.Debug().Msg("State: Introduce the song")
 
 	for _, panelist := range p.Panelists {
@@ -205,33 +401,66 @@ func (p *Play) IntroduceSong(_ Message) StateFunc {
 
 		p.host = panelist
 		panelist.SongPresented = true
+		panelist.Song.IntroducedAt = p.now()
 		panelist.ReviewGiven = true // Cannot review yourself
+		p.excludeTeammates(panelist)
 
 		logger.Logger.Info().
 			Str("hosts_name", p.host.Name).
 			Str("hosts_song", p.host.Song.String()).
 			Msg("Current presenter")
 
-		p.sendMessageToChannel(
-			fmt.Sprintf("The next song comes from the panelist %s and the song's details: %s",
-				panelist.Name, panelist.Song.String(),
-			),
-		)
-		return p.WaitForReviews
+		p.sendSongToChannel(panelist.Song, p.tr(msgNextSong, bold(panelist.Name), songLink(panelist.Song)))
+		p.openAudiencePoll()
+		if p.hostCommentary {
+			p.sendMessageToChannel(
+				p.tr(msgHowToComment, bold(panelist.Name), p.prefix, p.commandName(CommandComment)),
+			)
+			return StateWaitForCommentary
+		}
+		return StateWaitForReviews
 	}
 
-	return p.WaitForReviews
+	// Every remaining contestant of an elimination game was kicked
+	return StateStopGame
 }
 
-var reviewCommandMatcher = regexp.MustCompile(CommandReview)
-
-func (p *Play) WaitForReviews(msg Message) StateFunc {
+func (p *Play) waitForReviews(msg Message) State {
 	logger.Logger.Debug().Msg("State: Review and rate the song")
 
-	if !reviewCommandMatcher.MatchString(msg.Command) {
+	switch msg.Command {
+	case CommandKick:
+		if !p.Authorize(msg, ActionKick) {
+			return StateWaitForReviews
+		}
+		host := p.host
+		if !p.kickPanelist(msg) {
+			return StateInit
+		}
+		if !slices.Contains(p.Panelists, host) {
+			return p.nextRound()
+		}
+		if !p.isCurrentRoundReviewsDone() {
+			return StateWaitForReviews
+		}
+		return StateRevealReviews
+	case CommandSkip:
+		if !p.Authorize(msg, ActionSkip) {
+			return StateWaitForReviews
+		}
+		return p.skipSong(msg)
+	case CommandJoin:
+		p.addAudienceJuror(msg)
+		return StateWaitForReviews
+	case "":
+		// Resumed by a finished voice review
+		return p.reviewsDone()
+	}
+
+	if msg.Command != CommandReview {
 		logger.Logger.Warn().Interface("msg", msg).Msg("Not a command")
-		p.sendMessageToChannel("Aww cute, but it's a wrong command.")
-		return p.WaitForReviews
+		p.sendMessageToChannel(p.tr(msgWrongCommand))
+		return StateWaitForReviews
 	}
 
 	if p.host.uid == msg.FromID {
@@ -239,82 +468,193 @@ func (p *Play) WaitForReviews(msg Message) StateFunc {
 			msg.PlayerName,
 			msg.FromID,
 		)
-		p.sendMessageToPanelist(msg.ChatID, "You naughty. It's not possible to review own songs")
-		return p.WaitForReviews
+		p.sendMessageToPanelist(msg.ChatID, p.tr(msgOwnSong))
+		return StateWaitForReviews
 	}
 
-	var reviewer *Panelist
-	for _, rev := range p.Panelists {
-		if msg.FromID == rev.uid {
-			reviewer = rev
-			break
-		}
-	}
+	reviewer := p.findReviewer(msg.FromID)
 	if reviewer == nil {
 		logger.Logger.Error().Msgf("Couldn't find matching ID for user %s with ID %d",
 			msg.PlayerName, msg.FromID,
 		)
-		return p.WaitForReviews
+		return StateWaitForReviews
+	}
+	if teammates(reviewer, p.host) {
+		p.sendMessageToPanelist(msg.ChatID, p.tr(msgOwnTeamSong))
+		return StateWaitForReviews
 	}
+	if reviewer.ReviewGiven || p.voiceReviews[reviewer.uid] == p.host.Song {
+		p.sendMessageToPanelist(msg.ChatID, p.tr(msgAlreadyReviewed))
+		return StateWaitForReviews
+	}
+
+	if msg.Voice != "" {
+		// Rating is checked first, so that a voice note isn't saved and
+		// transcribed in vain
+		if _, err := parseRating(msg.Text, p.ratingMax); err != nil {
+			p.sendMessageToPanelist(msg.ChatID,
+				p.tr(msgVoiceRatingMissing, p.prefix, p.commandName(CommandReview), p.ratingMax),
+			)
+			return StateWaitForReviews
+		}
+		p.startVoiceReview(msg)
+		return p.reviewsDone()
+	}
+
+	if !p.addReview(reviewer, msg, msg.Text, "") {
+		return StateWaitForReviews
+	}
+	return p.reviewsDone()
+}
 
-	if err := p.host.AddReview(reviewer, msg.Text); err != nil {
+// addReview adds the review of the current song. The audio is the file name
+// of a voice review. Returns false when the review couldn't be added.
+func (p *Play) addReview(reviewer *Panelist, msg Message, review string, audio string) bool {
+	if err := p.host.AddReview(reviewer, review, p.ratingMax); err != nil {
 		reviewErr := ReviewError{}
 		if errors.As(err, &reviewErr) {
 			logger.Logger.Error().Err(reviewErr.Err).Msg("Couldn't parse review")
-			p.sendMessageToPanelist(msg.ChatID, reviewErr.ErrForUser)
+			p.sendMessageToPanelist(msg.ChatID, p.tr(reviewErr.ErrForUser, p.ratingMax))
 		} else {
 			logger.Logger.Error().Err(err).Interface("msg", msg).Msg("Couldn't add review")
-			p.sendMessageToPanelist(msg.ChatID, "Me confused two times. Que pasa¿")
+			p.sendMessageToPanelist(msg.ChatID, p.tr(msgConfusedTwice))
 		}
-		return p.WaitForReviews
+		return false
 	}
+	p.host.ReceivedReviews[len(p.host.ReceivedReviews)-1].Audio = audio
 	logger.Logger.Info().
 		Str("host_name", p.host.Name).
 		Interface("received_reviews", p.host.ReceivedReviews).
 		Msgf("Panelist %s reviewed the song %s", msg.PlayerName, p.host.Song.URL)
-	p.sendMessageToChannel(fmt.Sprintf("Panelist %s reviewed the song", msg.PlayerName))
+	if reviewer.audience {
+		p.announceProgress(p.tr(msgJurorReviewed, bold(msg.PlayerName)))
+	} else {
+		p.announceProgress(p.tr(msgPanelistReviewed, bold(msg.PlayerName)))
+	}
+	return true
+}
 
+// reviewsDone moves on to reveal the reviews once everybody has reviewed
+// the song.
+func (p *Play) reviewsDone() State {
 	if !p.isCurrentRoundReviewsDone() {
-		return p.WaitForReviews
+		return StateWaitForReviews
 	}
 
 	logger.Logger.Info().Msgf("Everybody has reviewed the song %s", p.host.Song.URL)
-	p.sendMessageToChannel("Everybody has reviewed the song, continuing...")
+	p.sendMessageToChannel(p.tr(msgAllReviewed))
 
-	return p.RevealReviews(msg) // Immediate transition
+	return StateRevealReviews
 }
 
-func (p *Play) RevealReviews(_ Message) StateFunc {
-	logger.Logger.Debug().Msg("State: Reveal the song reviews")
-
-	for _, r := range p.host.ReceivedReviews {
-		if os.Getenv("TEST_ENV") != "" {
-			// Avoid throttling
-			//nolint:gosec // False positive nagging, we do _use_ rand/v2
-			time.Sleep(time.Millisecond * time.Duration(rand.Int64N(800)))
+func (p *Play) revealReviews(_ Message) State {
+	if p.revealStyle == RevealPaced {
+		if p.reveal == nil {
+			logger.Logger.Debug().Msg("State: Reveal the song reviews one at a time")
+			p.startPacedReveal()
 		}
-
-		review := fmt.Sprintf("%s wrote: %s. The song rating was: %d/10", r.From, r.Review, r.Rating)
-		p.sendMessageToChannel(review)
+		// Messages are ignored until every review has been revealed
+		if !p.reveal.done {
+			return StateRevealReviews
+		}
+		p.reveal = nil
 	}
 
+	p.host.Song.RevealedAt = p.now()
 	p.countSongAverageScore()
-	finalScore := fmt.Sprintf("Eventually the song %s ended up catching %0.2f points",
-		p.host.Song.URL,
-		p.host.Song.AverageScore,
-	)
-	p.sendMessageToChannel(finalScore)
+	p.closeAudiencePoll(p.host.Song)
+	p.scoreJudges()
+	finalScore := p.tr(msgFinalScore, songLink(p.host.Song), p.host.Song.AverageScore)
+	if p.host.Song.AudienceVotes > 0 {
+		finalScore += p.tr(msgFinalAudienceScore, p.host.Song.AudienceScore)
+	}
+	if p.host.Song.Predicted {
+		finalScore += p.tr(msgFinalPrediction, bold(p.host.Name), p.host.Song.Prediction)
+	}
+
+	if p.revealStyle == RevealPaced {
+		p.sendMessageToChannel(finalScore)
+		return p.nextRound()
+	}
+
+	logger.Logger.Debug().Msg("State: Reveal the song reviews")
+	var sb strings.Builder
+	for _, r := range p.host.ReceivedReviews {
+		sb.WriteString(string(p.renderReview(r)) + "\n\n")
+	}
+	p.sendMessageToChannel(richText(sb.String()) + finalScore)
+
+	return p.nextRound()
+}
+
+// nextRound introduces the next unpresented song or ends the game when
+// every song has been presented.
+func (p *Play) nextRound() State {
+	// Song was skipped or its presenter kicked, discard the votes
+	p.closeAudiencePoll(nil)
+
+	for _, panelist := range p.Panelists {
+		if panelist.SongPresented {
+			continue
+		}
 
-	lastPanelist := p.Panelists[len(p.Panelists)-1]
-	if lastPanelist.uid != p.host.uid {
 		// For the next round, wipe given reviews flags
-		for _, panelist := range p.Panelists {
-			panelist.ReviewGiven = false
+		for _, pan := range p.Panelists {
+			pan.ReviewGiven = false
+		}
+		for _, juror := range p.AudienceJurors {
+			juror.ReviewGiven = false
 		}
-		return p.IntroduceSong(Message{})
+		return StateIntroduceSong
+	}
+	if p.elimination {
+		return p.eliminate()
 	}
 
-	return p.StopGame(Message{})
+	return StateStopGame
+}
+
+// skipSong stops collecting reviews for the current song. Reviews
+// received so far are revealed as usual.
+func (p *Play) skipSong(msg Message) State {
+	logger.Logger.Info().
+		Str("host_name", p.host.Name).
+		Msgf("Panelist %s with ID %d skipped the song %s", msg.PlayerName, msg.FromID, p.host.Song.URL)
+	p.sendMessageToChannel(p.tr(msgSkipped, bold(msg.PlayerName), bold(p.host.Name)))
+
+	if len(p.host.ReceivedReviews) == 0 {
+		return p.nextRound()
+	}
+
+	return StateRevealReviews
+}
+
+// kickPanelist removes the panelist named in the message text from the game.
+// Returns false when there are no panelists left to continue the game.
+func (p *Play) kickPanelist(msg Message) bool {
+	name := strings.TrimPrefix(strings.TrimSpace(msg.Text), "@")
+	idx := slices.IndexFunc(p.Panelists, func(pan *Panelist) bool {
+		return strings.EqualFold(pan.Name, name)
+	})
+	if idx == -1 {
+		p.sendMessageToPanelist(msg.ChatID, p.tr(msgNoSuchPanelist, name))
+		return true
+	}
+
+	kicked := p.Panelists[idx]
+	p.Panelists = slices.Delete(p.Panelists, idx, idx+1)
+	logger.Logger.Info().
+		Str("kicked_name", kicked.Name).
+		Int64("kicked_id", kicked.uid).
+		Msgf("Panelist %s with ID %d kicked a panelist", msg.PlayerName, msg.FromID)
+	p.sendMessageToChannel(p.tr(msgKicked, bold(kicked.Name), bold(msg.PlayerName)))
+
+	if len(p.Panelists) == 0 {
+		p.sendMessageToChannel(p.tr(msgNoPanelistsLeft))
+		return false
+	}
+
+	return true
 }
 
 func fileExists(path string) bool {
@@ -325,12 +665,12 @@ func fileExists(path string) bool {
 	return err == nil
 }
 
-func (p *Play) StopGame(_ Message) StateFunc {
-	p.sendMessageToChannel("State: Ending the game")
+func (p *Play) stopGame(_ Message) State {
+	p.sendMessageToChannel(p.tr(msgGameOver))
 
 	logger.Logger.Info().
 		Interface("output", p.Panelists).
-		Dur("duration", time.Since(p.StartedAt)).
+		Dur("duration", p.now().Sub(p.StartedAt)).
 		Msg("Game results")
 
 	// Sort panelists by the highest scorer in descending order
@@ -343,92 +683,152 @@ func (p *Play) StopGame(_ Message) StateFunc {
 		return 0
 	})
 
-	if p.resultsDirectory != nil { //nolint:nestif // Not that complex?
-		fout, fname, err := p.createResultsFile()
-		if err != nil {
-			logger.Logger.Error().Err(err).Msg("Failed to create results file")
-			p.sendMessageToChannel("Failed to create results file")
-		} else {
-			if err = renderResults(*p, fout); err != nil {
-				logger.Logger.Error().Err(err).Msg("Rendering the results failed")
-				p.sendMessageToChannel("Failed to render the results")
-			} else {
-				p.sendMessageToChannel(
-					fmt.Sprintf(
-						"Results are available in %s", p.resultsURL.JoinPath(fname).String(),
-					),
-				)
-			}
-		}
-		fout.Close()
-	} else {
-		if err := renderResults(*p, os.Stdout); err != nil {
-			logger.Logger.Error().Err(err).Msg("Rendering the results failed")
-			p.sendMessageToChannel("Failed to render the results")
-		}
+	if p.elimination {
+		p.rankByElimination()
 	}
 
-	winner := p.Panelists[0]
-	for i := 1; i < len(p.Panelists)-1; i++ {
-		panelist := p.Panelists[i]
-		if panelist.Song.AverageScore > winner.Song.AverageScore {
-			winner = panelist
-		}
-	}
-	p.sendMessageToChannel(
-		fmt.Sprintf("Game has ended. The winner song came from %s and was %s with %.2f average score",
-			winner.Name,
-			winner.Song.URL,
-			winner.Song.AverageScore,
-		),
-	)
+	p.publishResults()
 
-	p.ClearGame()
+	winner := p.Panelists[0]
+	p.sendMessageToChannel(p.tr(msgWinner, bold(winner.Name), songLink(winner.Song), winner.Song.AverageScore))
+	p.announceTeamScores()
+	p.announceBestPredictions()
+	p.announceBestJudges()
 
-	return nil
+	return StateInit
 }
 
 // ClearGame should be called when the game is stopped so it
 // will set all the needed values back to their initial values.
 func (p *Play) ClearGame() {
-	p.sendMessageToChannel("Ending the game")
-	p.gameActive = false
+	p.cancelReveal()
+	p.closeScoreboard()
+	p.sendMessageToChannel(p.tr(msgEndingGame))
+	p.state = StateInit
+	p.closeEventLog()
 	p.host = nil
 	p.StartedAt = time.Time{}
+	p.EndedAt = time.Time{}
 	p.Panelists = []*Panelist{}
+	p.AudienceJurors = []*Panelist{}
+	p.EliminationRounds = nil
+	p.sharedSongs = map[int64]Message{}
+	p.voiceReviews = map[int64]*Song{}
 	p.gameStarterUID = 0
+	p.pollMessageID = 0
+	p.bracket = nil
+	p.match = nil
+	p.matchRound = 0
+	p.votes = nil
+	p.interrupted = false
 	p.allSongsSubmitted = false
 }
 
-func (p *Play) createResultsFile() (*os.File, string, error) {
-	fname := fmt.Sprintf("jukebox_jury_results_%s.html", time.Now().Local().Format("2006-01-02T150405"))
-	fpath := filepath.Join(*p.resultsDirectory, fname)
-	if fileExists(fpath) {
-		return nil, "", fmt.Errorf("file %q already exists", fpath)
+// Shutdown interrupts the running game when the bot is going down.
+// The results collected so far are saved and the channel is told about it.
+func (p *Play) Shutdown() {
+	if p.state == StateInit {
+		return
+	}
+
+	logger.Logger.Info().Stringer("state", p.state).Msg("Interrupting the game")
+	p.recordEvent(Event{Type: EventShutdown})
+	p.interrupted = true
+	p.closeAudiencePoll(nil)
+
+	p.sendMessageToChannel(p.tr(msgShutdown))
+	p.publishResults()
+	p.ClearGame()
+}
+
+// Interrupted tells whether the game was interrupted before all
+// the songs were reviewed.
+func (p Play) Interrupted() bool {
+	return p.interrupted
+}
+
+// publishResults saves the results and tells the channel where to find
+// them. Without the results directory results are printed to stdout.
+func (p *Play) publishResults() {
+	p.EndedAt = p.now()
+	if p.resultsDirectory == nil {
+		if err := renderResults(*p, os.Stdout); err != nil {
+			logger.Logger.Error().Err(err).Msg("Rendering the results failed")
+			p.sendMessageToChannel(p.tr(msgRenderFailed))
+		}
+		return
 	}
 
-	fout, err := os.Create(fpath)
+	fname := fmt.Sprintf("jukebox_jury_results_%s.html", p.now().Local().Format("2006-01-02T150405"))
+	if fileExists(filepath.Join(*p.resultsDirectory, fname)) {
+		logger.Logger.Error().Str("filename", fname).Msg("Results file already exists")
+		p.sendMessageToChannel(p.tr(msgSaveFailed))
+		return
+	}
+	err := p.writeResultsFile(fname, func(w io.Writer) error { return renderResults(*p, w) })
 	if err != nil {
-		return nil, "", fmt.Errorf("file %q creation: %w", fpath, err)
+		logger.Logger.Error().Err(err).Msg("Failed to write results file")
+		p.sendMessageToChannel(p.tr(msgSaveFailed))
+		return
+	}
+	// Tournaments are seeded from the machine readable copy of the results
+	if err = p.writeResultsFile(strings.TrimSuffix(fname, ".html")+".json", p.writeSongResults); err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to write results JSON")
 	}
 
-	return fout, fname, nil
+	resultsURL := p.resultsURL.JoinPath(fname).String()
+	if p.interrupted {
+		p.sendMessageToChannel(p.tr(msgPartialResults, resultsURL))
+	} else {
+		p.sendMessageToChannel(p.tr(msgResults, resultsURL))
+	}
+}
+
+// writeResultsFile renders a file into a temporary file in the results
+// directory, which is renamed in place once complete. This way an
+// interrupted write never leaves a half-written results file behind.
+func (p *Play) writeResultsFile(fname string, render func(io.Writer) error) error {
+	fpath := filepath.Join(*p.resultsDirectory, fname)
+	// Temporary file is created next to the results, as the rename can't
+	// cross file systems. Empty directory would mean os.TempDir().
+	fout, err := os.CreateTemp(filepath.Dir(fpath), "."+fname+".*")
+	if err != nil {
+		return fmt.Errorf("file %q creation: %w", fpath, err)
+	}
+	defer os.Remove(fout.Name()) // No-op after a successful rename
+
+	if err = render(fout); err != nil {
+		fout.Close()
+		return err
+	}
+	//nolint:gosec // Results are meant to be served by a web server
+	if err = fout.Chmod(0o644); err != nil {
+		fout.Close()
+		return fmt.Errorf("file %q permissions: %w", fpath, err)
+	}
+	if err = fout.Close(); err != nil {
+		return fmt.Errorf("file %q close: %w", fpath, err)
+	}
+	if err = os.Rename(fout.Name(), fpath); err != nil {
+		return fmt.Errorf("file %q rename: %w", fpath, err)
+	}
+
+	return nil
 }
 
 func (p *Play) countSongAverageScore() {
-	if len(p.host.ReceivedReviews) == 1 {
-		p.host.Song.AverageScore = float64(p.host.ReceivedReviews[0].Rating)
+	song := p.host.Song
+	if p.audienceMode == AudienceSeparate {
+		song.AverageScore, _ = averageRating(p.host.ReceivedReviews, true, false)
+		song.AudienceScore, song.AudienceVotes = averageRating(p.host.ReceivedReviews, false, true)
 	} else {
-		sum := 0
-		for _, r := range p.host.ReceivedReviews {
-			sum += r.Rating
-		}
-		p.host.Song.AverageScore = float64(sum) / float64(len(p.host.ReceivedReviews))
+		song.AverageScore, _ = averageRating(p.host.ReceivedReviews, true, true)
 	}
 
 	logger.Logger.Info().
 		Str("song_presenter", p.host.Name).
 		Float64("song_average_score", p.host.Song.AverageScore).
+		Float64("song_audience_score", p.host.Song.AudienceScore).
 		Msgf("Counted scores for the song %s", p.host.Song.URL)
 }
 
@@ -442,15 +842,21 @@ func (p *Play) addPanelist(msg Message) bool {
 			return false
 		}
 	}
-	p.Panelists = append(p.Panelists, NewPanelist(msg.PlayerName, msg.FromID))
-	logger.Logger.Info().Msgf("Panelist %s with ID %d joined the game", msg.PlayerName, msg.FromID)
+	panelist := NewPanelist(msg.PlayerName, msg.FromID)
+	if p.teams {
+		panelist.Team = p.teamName(msg.Text)
+	}
+	p.Panelists = append(p.Panelists, panelist)
+	logger.Logger.Info().
+		Str("team", panelist.Team).
+		Msgf("Panelist %s with ID %d joined the game", msg.PlayerName, msg.FromID)
 
 	return true
 }
 
 type SongError struct {
 	Err        string
-	ErrForUser string
+	ErrForUser messageID
 }
 
 func (s SongError) Error() string {
@@ -458,11 +864,21 @@ func (s SongError) Error() string {
 }
 
 func (p *Play) addSong(msg Message) error {
-	panelist := &Panelist{}
+	var panelist *Panelist
 	for _, pan := range p.Panelists {
 		if pan.uid != msg.FromID {
 			continue
 		}
+		if pan.EliminatedIn > 0 {
+			return SongError{
+				ErrForUser: msgEliminatedSong,
+				Err: fmt.Sprintf("panelist %s with ID %d was eliminated in round %d",
+					msg.PlayerName,
+					msg.FromID,
+					pan.EliminatedIn,
+				),
+			}
+		}
 
 		if !pan.SongSubmitted {
 			panelist = pan
@@ -470,7 +886,7 @@ func (p *Play) addSong(msg Message) error {
 		}
 
 		return SongError{
-			ErrForUser: "Song already added",
+			ErrForUser: msgSongAlreadyAdded,
 			Err: fmt.Sprintf("panelist %s with ID %d has already added the song",
 				msg.PlayerName,
 				msg.FromID,
@@ -479,7 +895,7 @@ func (p *Play) addSong(msg Message) error {
 	}
 	if panelist == nil {
 		return SongError{
-			ErrForUser: "Was, bist du echt?",
+			ErrForUser: msgNotInGame,
 			Err: fmt.Sprintf("panelist %s with ID %d tried to add song, although not in the game",
 				msg.PlayerName,
 				msg.FromID,
@@ -487,12 +903,22 @@ func (p *Play) addSong(msg Message) error {
 		}
 	}
 
-	if err := panelist.AddSong(msg); err != nil {
+	maxPrediction := 0
+	if p.scorePredictions {
+		maxPrediction = p.ratingMax
+	}
+	if err := panelist.AddSong(msg, maxPrediction); err != nil {
+		if errors.Is(err, ErrPrediction) {
+			return SongError{
+				ErrForUser: msgPredictionTooHigh,
+				Err:        fmt.Sprintf("panelist %s with ID %d: %s", msg.PlayerName, msg.FromID, err),
+			}
+		}
 		logger.Logger.Warn().
 			Interface("msg", msg).
 			Msgf("Panelist %s with ID %d presented malformed song", msg.PlayerName, msg.FromID)
 		return SongError{
-			ErrForUser: "Song given in the malformed form",
+			ErrForUser: msgMalformedSong,
 			Err: fmt.Sprintf("panelist %s with ID %d presented malformed song: %q",
 				msg.PlayerName,
 				msg.FromID,
@@ -508,39 +934,6 @@ func (p *Play) addSong(msg Message) error {
 	return nil
 }
 
-func (p *Play) shuffleHost() {
-	// In test mode shuffle is skipped to make order comparable
-	if os.Getenv("TEST_MODE") != "" {
-		return
-	}
-
-	logger.Logger.Debug().Msg("State: Shuffle the host")
-
-	rand.Shuffle(len(p.Panelists), func(i, j int) {
-		p.Panelists[i], p.Panelists[j] = p.Panelists[j], p.Panelists[i]
-	})
-}
-
-// sendMessageToChannel sends text parameter to Telegram channel and logs failed sends.
-func (p *Play) sendMessageToChannel(text string) {
-	msg := tgbotapi.NewMessage(p.chatID, text)
-	_, err := p.bot.Send(msg)
-	if err != nil {
-		logger.Logger.Error().Err(err).Str("payload", text).Msg("Error sending channel message")
-		return
-	}
-}
-
-// sendMessageToPanelist sends text parameter to panelist and logs failed sends.
-func (p *Play) sendMessageToPanelist(chatID int64, text string) {
-	msg := tgbotapi.NewMessage(chatID, text)
-	_, err := p.bot.Send(msg)
-	if err != nil {
-		logger.Logger.Error().Err(err).Str("payload", text).Msg("Error sending user message")
-		return
-	}
-}
-
 func (p *Play) isAllSongsSubmitted() bool {
 	for _, panelist := range p.Panelists {
 		if !panelist.SongSubmitted {
@@ -552,13 +945,15 @@ func (p *Play) isAllSongsSubmitted() bool {
 }
 
 func (p *Play) isCurrentRoundReviewsDone() bool {
-	expectedReviewsCount := len(p.Panelists) - 1
-	if len(p.host.ReceivedReviews) != expectedReviewsCount {
+	for _, panelist := range p.Panelists {
+		if panelist.ReviewGiven {
+			continue
+		}
 		logger.Logger.Debug().
 			Str("host_name", p.host.Name).
 			Interface("received_reviews", p.host.ReceivedReviews).
 			Msgf("Expected reviews %d, so far received %d",
-				expectedReviewsCount,
+				len(p.Panelists)-1,
 				len(p.host.ReceivedReviews),
 			)
 		return false
@@ -566,70 +961,3 @@ func (p *Play) isCurrentRoundReviewsDone() bool {
 
 	return true
 }
-
-func ParseToMessage(u tgbotapi.Update) (Message, error) {
-	msg := Message{}
-
-	splt := strings.SplitN(u.Message.Text, " ", 3)
-	switch len(splt) {
-	case 2:
-		msg.Command = splt[1]
-		msg.Text = strings.Join(splt[2:], " ")
-	case 3:
-		msg.Command = splt[1]
-		msg.Text = strings.Join(splt[2:], " ")
-	default:
-		return Message{}, ErrInvaliSyntax
-	}
-
-	if splt[0] != JukeboxJuryPrefix {
-		return Message{}, ErrInvaliSyntax
-	}
-
-	if u.Message.From.UserName == "" {
-		msg.PlayerName = u.Message.From.FirstName
-	} else {
-		msg.PlayerName = u.Message.From.UserName
-	}
-
-	msg.FromID = u.Message.From.ID
-	msg.ChatID = u.Message.Chat.ID
-
-	return msg, nil
-}
-
-// func HostNewGame(g *GamePlay, bot TelegramBoter) {
-// 	state := g.Init
-// 	u := tgbotapi.NewUpdate(0)
-// 	u.Timeout = 30
-// 	for update := range bot.GetUpdatesChan(u) {
-// 		if update.Message == nil {
-// 			continue
-// 		}
-
-// 		if strings.Fields(update.Message.Text)[0] != jukeboxJuryPrefix {
-// 			continue
-// 		}
-
-// 		tmp := strings.SplitN(update.Message.Text, " ", 2)
-// 		if len(tmp) < 2 {
-// 			continue
-// 		}
-// 		cmd := tmp[1]
-// 		// msg := tmp[2:]
-
-// 		switch cmd {
-// 		case "aloita":
-// 			g = New(bot, update.Message.Chat.ID, update.Message.From.ID)
-// 			state = g.StartGame
-// 			logger.Logger.Info().Msg("Starting a new game")
-
-// 		case "lopeta":
-// 			g.StopGame(update)
-// 			logger.Logger.Info().Msg("Game stopped")
-// 			g = nil
-// 		}
-
-// 		state = state(update)
-// 	}
-// }
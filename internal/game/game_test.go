@@ -3,8 +3,11 @@ package game
 import (
 	"errors"
 	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
 	"reflect"
-	"runtime"
+	"slices"
 	"strings"
 	"testing"
 
@@ -15,12 +18,22 @@ import (
 type mockTelegramBot struct {
 	mGetUpdatesChan  func(config tgbotapi.UpdateConfig) tgbotapi.UpdatesChannel
 	mSend            func(c tgbotapi.Chattable) (tgbotapi.Message, error)
+	mStopPoll        func(c tgbotapi.StopPollConfig) (tgbotapi.Poll, error)
+	chatAdmins       []tgbotapi.ChatMember
 	receivedMessages []string
+	requests         []tgbotapi.Chattable
+	madeRequests     []tgbotapi.Params
+	fileURLs         map[string]string // Download URLs by the file ID
 }
 
 func (m *mockTelegramBot) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
-	if msg, ok := c.(tgbotapi.MessageConfig); ok {
+	switch msg := c.(type) {
+	case tgbotapi.MessageConfig:
 		m.receivedMessages = append(m.receivedMessages, msg.Text)
+	case tgbotapi.AudioConfig:
+		m.receivedMessages = append(m.receivedMessages, msg.Caption)
+	case tgbotapi.VoiceConfig:
+		m.receivedMessages = append(m.receivedMessages, msg.Caption)
 	}
 
 	return m.mSend(c)
@@ -30,11 +43,84 @@ func (m *mockTelegramBot) GetUpdatesChan(config tgbotapi.UpdateConfig) tgbotapi.
 	return m.mGetUpdatesChan(config)
 }
 
-func TestGamePlayWith3Panelists(t *testing.T) {
+func (m *mockTelegramBot) GetChatAdministrators(_ tgbotapi.ChatAdministratorsConfig) ([]tgbotapi.ChatMember, error) {
+	return m.chatAdmins, nil
+}
+
+func (m *mockTelegramBot) GetFileDirectURL(fileID string) (string, error) {
+	if fileURL, ok := m.fileURLs[fileID]; ok {
+		return fileURL, nil
+	}
+	return "", fmt.Errorf("no file %q", fileID)
+}
+
+func (m *mockTelegramBot) StopPoll(c tgbotapi.StopPollConfig) (tgbotapi.Poll, error) {
+	return m.mStopPoll(c)
+}
+
+// MakeRequest records the request, and the text of a sent message like Send.
+func (m *mockTelegramBot) MakeRequest(endpoint string, params tgbotapi.Params) (*tgbotapi.APIResponse, error) {
+	m.madeRequests = append(m.madeRequests, params)
+	if endpoint == "sendMessage" {
+		m.receivedMessages = append(m.receivedMessages, params["text"])
+	}
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+func (m *mockTelegramBot) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	m.requests = append(m.requests, c)
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+// testMessage returns a message sent by the user in a private chat.
+func testMessage(from *tgbotapi.User, text string) tgbotapi.Message {
+	return tgbotapi.Message{Chat: &tgbotapi.Chat{ID: from.ID}, From: from, Text: text}
+}
+
+// scriptStep is a message of a scripted game and the state the game
+// should be in after it.
+type scriptStep struct {
+	update   tgbotapi.Message
+	want     State
+	response string // Last message sent, if any
+}
+
+// newScriptedGame creates a game whose bot records the sent messages.
+// The songs are presented in the order the panelists joined.
+func newScriptedGame(t *testing.T, opts ...PlayOption) (*Play, *mockTelegramBot) {
 	t.Helper()
+	bot := &mockTelegramBot{
+		mSend: func(_ tgbotapi.Chattable) (tgbotapi.Message, error) {
+			return tgbotapi.Message{}, nil
+		},
+		receivedMessages: []string{},
+	}
+	return New(bot, 1, append([]PlayOption{withoutShuffle()}, opts...)...), bot
+}
+
+// runScript feeds the messages to the game and checks the state, and the
+// last message sent, after each of them.
+func runScript(t *testing.T, p *Play, bot *mockTelegramBot, steps []scriptStep) {
+	t.Helper()
+	for i, step := range steps {
+		msg, err := ParseToMessage(tgbotapi.Update{Message: &step.update})
+		if err != nil {
+			t.Fatalf("Failed to parse %d %q: %#v", i, step.update.Text, err)
+		}
+		p.Handle(msg)
+		if p.State() != step.want {
+			t.Fatalf("Message %d %q: state = %s, want %s", i, step.update.Text, p.State(), step.want)
+		}
+		sent := bot.receivedMessages
+		if step.response != "" && sent[len(sent)-1] != step.response {
+			t.Errorf("Message %d %q: response = %q, want %q",
+				i, step.update.Text, sent[len(sent)-1], step.response)
+		}
+	}
+}
 
-	// Skip shuffling the host to keep tests deterministic
-	t.Setenv("TEST_MODE", "true")
+func TestGamePlayWith3Panelists(t *testing.T) {
+	t.Helper()
 
 	mockBot := mockTelegramBot{
 		mGetUpdatesChan: func(_ tgbotapi.UpdateConfig) tgbotapi.UpdatesChannel {
@@ -130,8 +216,7 @@ func TestGamePlayWith3Panelists(t *testing.T) {
 		},
 	}
 
-	p := New(&mockBot, 12345678, WithOutputDirectory(nil))
-	state := p.StartGame
+	p := New(&mockBot, 12345678, WithOutputDirectory(nil), withoutShuffle())
 
 	for i, update := range updates {
 		msg, err := ParseToMessage(tgbotapi.Update{Message: &update})
@@ -139,65 +224,55 @@ func TestGamePlayWith3Panelists(t *testing.T) {
 			t.Fatalf("Failed to parse %d %q: %#v", i, update.Text, err)
 		}
 
-		fnPtr := reflect.ValueOf(state).Pointer()
-		fnName := strings.Split(runtime.FuncForPC(fnPtr).Name(), ".")[2]
-		fmt.Println(i, "STATE BEFORE:", fnName)
-
-		state = state(msg)
-
-		fnPtr = reflect.ValueOf(state).Pointer()
-		if fnPtr != 0 {
-			fnName = strings.Split(runtime.FuncForPC(fnPtr).Name(), ".")[2]
-			fmt.Println(i, "STATE AFTER:", fnName)
-		} else {
-			fmt.Println(i, "STATE AFTER: nil")
-		}
+		p.Handle(msg)
 	}
 
 	expectedMessages := []string{
-		fmt.Sprintf("User Santana started a new game, join by using command: %s %s",
-			JukeboxJuryPrefix, CommandJoin),
-		"User Pjotr joined the game",
-		"User Jesus joined the game",
-		"User Santana wants to proceed, continuing...",
+		"User <b>Santana</b> started a new game, join by using command: levyraati liity",
+		"User <b>Pjotr</b> joined the game",
+		"User <b>Jesus</b> joined the game",
+		"User <b>Santana</b> wants to proceed, continuing...",
 		"Add song with the following command and format in private chat with the bot: " +
-			"levyraati (esitä|esitys) description here https://link-as-last-item",
+			"levyraati esitä description here https://link-as-last-item",
 		"Add review similar way (max score is 10, only integers): " +
-			"levyraati (arvio|arvioi|arvostele) description here 0/10",
-		"Panelist Santana added a song",
-		"Panelist Pjotr added a song",
-		"Panelist Jesus added a song",
+			"levyraati arvioi description here 0/10",
+		"Panelist <b>Santana</b> added a song",
+		"Panelist <b>Pjotr</b> added a song",
+		"Panelist <b>Jesus</b> added a song",
 		"All songs submitted, continuing...",
 		// First song introduction & review
-		"The next song comes from the panelist Santana and the song's details: Description: " +
-			"My favourite song, URL: https://example.com/satan_you_rock",
-		"Panelist Jesus reviewed the song",
-		"Panelist Pjotr reviewed the song",
+		`The next song comes from the panelist <b>Santana</b>: ` +
+			`<a href="https://example.com/satan_you_rock">My favourite song</a>`,
+		"Panelist <b>Jesus</b> reviewed the song",
+		"Panelist <b>Pjotr</b> reviewed the song",
 		"Everybody has reviewed the song, continuing...",
-		"Jesus wrote: Great song1. The song rating was: 10/10",
-		"Pjotr wrote: Nice song such wow1. The song rating was: 5/10",
-		"Eventually the song https://example.com/satan_you_rock ended up catching 7.50 points",
+		"<b>Jesus</b> wrote: Great song1. The song rating was: 10/10\n\n" +
+			"<b>Pjotr</b> wrote: Nice song such wow1. The song rating was: 5/10\n\n" +
+			`Eventually the song <a href="https://example.com/satan_you_rock">My favourite song</a> ` +
+			"ended up catching 7.50 points",
 		// Second song introduction & review
-		"The next song comes from the panelist Pjotr and the song's details: Description: " +
-			"I happen to like it, URL: https://example.com/pjotr",
-		"Panelist Jesus reviewed the song",
-		"Panelist Santana reviewed the song",
+		`The next song comes from the panelist <b>Pjotr</b>: ` +
+			`<a href="https://example.com/pjotr">I happen to like it</a>`,
+		"Panelist <b>Jesus</b> reviewed the song",
+		"Panelist <b>Santana</b> reviewed the song",
 		"Everybody has reviewed the song, continuing...",
-		"Jesus wrote: Great song2. The song rating was: 10/10",
-		"Santana wrote: Terrible song2. The song rating was: 1/10",
-		"Eventually the song https://example.com/pjotr ended up catching 5.50 points",
+		"<b>Jesus</b> wrote: Great song2. The song rating was: 10/10\n\n" +
+			"<b>Santana</b> wrote: Terrible song2. The song rating was: 1/10\n\n" +
+			`Eventually the song <a href="https://example.com/pjotr">I happen to like it</a> ` +
+			"ended up catching 5.50 points",
 		// Third song introduction & review
-		"The next song comes from the panelist Jesus and the song's details: Description: Hallelujah 🤘, " +
-			"URL: https://example.com/hesus",
-		"Panelist Santana reviewed the song",
-		"Panelist Pjotr reviewed the song",
+		`The next song comes from the panelist <b>Jesus</b>: ` +
+			`<a href="https://example.com/hesus">Hallelujah 🤘</a>`,
+		"Panelist <b>Santana</b> reviewed the song",
+		"Panelist <b>Pjotr</b> reviewed the song",
 		"Everybody has reviewed the song, continuing...",
-		"Santana wrote: Terrible song3. The song rating was: 1/10",
-		"Pjotr wrote: Nice song such wow3. The song rating was: 5/10",
-		"Eventually the song https://example.com/hesus ended up catching 3.00 points",
-		"State: Ending the game",
-		"Game has ended. The winner song came from Santana and was https://example.com/satan_you_rock " +
-			"with 7.50 average score",
+		"<b>Santana</b> wrote: Terrible song3. The song rating was: 1/10\n\n" +
+			"<b>Pjotr</b> wrote: Nice song such wow3. The song rating was: 5/10\n\n" +
+			`Eventually the song <a href="https://example.com/hesus">Hallelujah 🤘</a> ` +
+			"ended up catching 3.00 points",
+		"The game is over, here are the results",
+		"Game has ended. The winner song came from <b>Santana</b> and was " +
+			`<a href="https://example.com/satan_you_rock">My favourite song</a> with 7.50 average score`,
 		"Ending the game",
 	}
 
@@ -221,9 +296,6 @@ func TestGamePlayWith3Panelists(t *testing.T) {
 func TestGamePlayWith2Panelists(t *testing.T) {
 	t.Helper()
 
-	// Skip shuffling the host to keep tests deterministic
-	t.Setenv("TEST_MODE", "true")
-
 	mockBot := mockTelegramBot{
 		mGetUpdatesChan: func(_ tgbotapi.UpdateConfig) tgbotapi.UpdatesChannel {
 			return nil
@@ -285,8 +357,7 @@ func TestGamePlayWith2Panelists(t *testing.T) {
 		},
 	}
 
-	p := New(&mockBot, 123456789, WithOutputDirectory(nil))
-	state := p.StartGame
+	p := New(&mockBot, 123456789, WithOutputDirectory(nil), withoutShuffle())
 
 	for i, update := range updates {
 		msg, err := ParseToMessage(tgbotapi.Update{Message: &update})
@@ -294,53 +365,42 @@ func TestGamePlayWith2Panelists(t *testing.T) {
 			t.Fatalf("Failed to parse %d %q: %#v", i, update.Text, err)
 		}
 
-		fnPtr := reflect.ValueOf(state).Pointer()
-		fnName := strings.Split(runtime.FuncForPC(fnPtr).Name(), ".")[2]
-		fmt.Println(i, "STATE BEFORE:", fnName)
-
-		state = state(msg)
-		if state == nil && i < len(updates)-1 {
-			t.Fatalf("Premature exit, state was already nil although there were updates in the pipe")
-		}
-
-		fnPtr = reflect.ValueOf(state).Pointer()
-		if fnPtr != 0 {
-			fnName = strings.Split(runtime.FuncForPC(fnPtr).Name(), ".")[2]
-			fmt.Println(i, "STATE AFTER:", fnName)
-		} else {
-			fmt.Println(i, "STATE AFTER: nil")
+		p.Handle(msg)
+		if p.State() == StateInit && i < len(updates)-1 {
+			t.Fatalf("Premature exit, game already ended although there were updates in the pipe")
 		}
 	}
 
 	expectedMessages := []string{
-		fmt.Sprintf("User Santana started a new game, join by using command: %s %s",
-			JukeboxJuryPrefix, CommandJoin),
-		"User Jesus joined the game",
-		"User Santana wants to proceed, continuing...",
+		"User <b>Santana</b> started a new game, join by using command: levyraati liity",
+		"User <b>Jesus</b> joined the game",
+		"User <b>Santana</b> wants to proceed, continuing...",
 		"Add song with the following command and format in private chat with the bot: " +
-			"levyraati (esitä|esitys) description here https://link-as-last-item",
+			"levyraati esitä description here https://link-as-last-item",
 		"Add review similar way (max score is 10, only integers): " +
-			"levyraati (arvio|arvioi|arvostele) description here 0/10",
-		"Panelist Santana added a song",
-		"Panelist Jesus added a song",
+			"levyraati arvioi description here 0/10",
+		"Panelist <b>Santana</b> added a song",
+		"Panelist <b>Jesus</b> added a song",
 		"All songs submitted, continuing...",
 		// First song introduction & review
-		"The next song comes from the panelist Santana and the song's details: Description: " +
-			"My favourite song, URL: https://example.com/satan_you_rock",
-		"Panelist Jesus reviewed the song",
+		`The next song comes from the panelist <b>Santana</b>: ` +
+			`<a href="https://example.com/satan_you_rock">My favourite song</a>`,
+		"Panelist <b>Jesus</b> reviewed the song",
 		"Everybody has reviewed the song, continuing...",
-		"Jesus wrote: Great song1. The song rating was: 10/10",
-		"Eventually the song https://example.com/satan_you_rock ended up catching 10.00 points",
+		"<b>Jesus</b> wrote: Great song1. The song rating was: 10/10\n\n" +
+			`Eventually the song <a href="https://example.com/satan_you_rock">My favourite song</a> ` +
+			"ended up catching 10.00 points",
 		// Second song introduction & review
-		"The next song comes from the panelist Jesus and the song's details: Description: Hallelujah 🤘, " +
-			"URL: https://example.com/hesus",
-		"Panelist Santana reviewed the song",
+		`The next song comes from the panelist <b>Jesus</b>: ` +
+			`<a href="https://example.com/hesus">Hallelujah 🤘</a>`,
+		"Panelist <b>Santana</b> reviewed the song",
 		"Everybody has reviewed the song, continuing...",
-		"Santana wrote: Terrible song1. The song rating was: 1/10",
-		"Eventually the song https://example.com/hesus ended up catching 1.00 points",
-		"State: Ending the game",
-		"Game has ended. The winner song came from Santana and was https://example.com/satan_you_rock with " +
-			"10.00 average score",
+		"<b>Santana</b> wrote: Terrible song1. The song rating was: 1/10\n\n" +
+			`Eventually the song <a href="https://example.com/hesus">Hallelujah 🤘</a> ` +
+			"ended up catching 1.00 points",
+		"The game is over, here are the results",
+		"Game has ended. The winner song came from <b>Santana</b> and was " +
+			`<a href="https://example.com/satan_you_rock">My favourite song</a> with 10.00 average score`,
 		"Ending the game",
 	}
 
@@ -472,3 +532,170 @@ func TestNewMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestGamePlayWithAudienceJuror(t *testing.T) {
+
+	mockBot := mockTelegramBot{
+		mSend: func(_ tgbotapi.Chattable) (tgbotapi.Message, error) {
+			return tgbotapi.Message{}, nil
+		},
+		receivedMessages: []string{},
+	}
+
+	panelistSantana := &tgbotapi.User{ID: 666, UserName: "Santana"}
+	panelistJesus := &tgbotapi.User{ID: 123, UserName: "Jesus"}
+	jurorPjotr := &tgbotapi.User{ID: 7, UserName: "Pjotr"}
+
+	updates := []tgbotapi.Message{
+		testMessage(panelistSantana, "levyraati aloita"),
+		testMessage(panelistJesus, "levyraati liity"),
+		testMessage(panelistSantana, "levyraati jatka"),
+		testMessage(jurorPjotr, "levyraati liity"),
+		testMessage(panelistSantana, "levyraati esitä Song1 https://example.com/1"),
+		testMessage(panelistJesus, "levyraati esitä Song2 https://example.com/2"),
+		testMessage(jurorPjotr, "levyraati arvioi Audience1 2/10"),
+		testMessage(jurorPjotr, "levyraati arvioi Audience1 again 3/10"),
+		testMessage(panelistJesus, "levyraati arvioi Jury1 8/10"),
+		testMessage(panelistSantana, "levyraati arvioi Jury2 4/10"),
+	}
+
+	p := New(&mockBot, 1, WithOutputDirectory(nil), withoutShuffle(), WithAudienceJurors(AudienceSeparate))
+	for i, update := range updates {
+		msg, err := ParseToMessage(tgbotapi.Update{Message: &update})
+		if err != nil {
+			t.Fatalf("Failed to parse %d %q: %#v", i, update.Text, err)
+		}
+		p.Handle(msg)
+	}
+	if p.State() != StateInit {
+		t.Fatalf("Game didn't end after the last review")
+	}
+
+	expectedMessages := []string{
+		"User <b>Pjotr</b> joined the audience jury",
+		"Audience juror <b>Pjotr</b> reviewed the song",
+		"You have already reviewed this song",
+		"<b>Pjotr</b> (audience) wrote: Audience1. The song rating was: 2/10\n\n" +
+			"<b>Jesus</b> wrote: Jury1. The song rating was: 8/10\n\n" +
+			`Eventually the song <a href="https://example.com/1">Song1</a> ended up catching 8.00 points ` +
+			"and the audience gave it 2.00 points",
+		"<b>Santana</b> wrote: Jury2. The song rating was: 4/10\n\n" +
+			`Eventually the song <a href="https://example.com/2">Song2</a> ended up catching 4.00 points`,
+	}
+	for _, expected := range expectedMessages {
+		if !slices.Contains(mockBot.receivedMessages, expected) {
+			t.Errorf("Message %q not sent, got:\n%s",
+				expected, strings.Join(mockBot.receivedMessages, "\n"))
+		}
+	}
+}
+
+func TestPanelistReviewsOnce(t *testing.T) {
+	p, bot := newScriptedGame(t, WithOutputDirectory(nil))
+
+	santana := &tgbotapi.User{ID: 666, UserName: "Santana"}
+	jesus := &tgbotapi.User{ID: 123, UserName: "Jesus"}
+	pjotr := &tgbotapi.User{ID: 7, UserName: "Pjotr"}
+	runScript(t, p, bot, []scriptStep{
+		{update: testMessage(santana, "levyraati aloita"), want: StateWaitPanelistsToJoin},
+		{update: testMessage(jesus, "levyraati liity"), want: StateWaitPanelistsToJoin},
+		{update: testMessage(pjotr, "levyraati liity"), want: StateWaitPanelistsToJoin},
+		{update: testMessage(santana, "levyraati jatka"), want: StateAddSong},
+		{update: testMessage(santana, "levyraati esitä Song1 https://example.com/1"), want: StateAddSong},
+		{update: testMessage(jesus, "levyraati esitä Song2 https://example.com/2"), want: StateAddSong},
+		{update: testMessage(pjotr, "levyraati esitä Song3 https://example.com/3"), want: StateWaitForReviews},
+		{update: testMessage(jesus, "levyraati arvioi Good 8/10"), want: StateWaitForReviews},
+		{
+			update:   testMessage(jesus, "levyraati arvioi Changed my mind 2/10"),
+			want:     StateWaitForReviews,
+			response: "You have already reviewed this song",
+		},
+		{update: testMessage(pjotr, "levyraati arvioi Fine 6/10"), want: StateWaitForReviews},
+	})
+
+	if reviews := p.Panelists[0].ReceivedReviews; len(reviews) != 2 {
+		t.Errorf("Song got %d reviews, want 2", len(reviews))
+	}
+	if score := p.Panelists[0].Song.AverageScore; score != 7 {
+		t.Errorf("Average score = %.2f, want 7.00", score)
+	}
+}
+
+func TestShutdownWritesPartialResults(t *testing.T) {
+	resultsDir := t.TempDir()
+	mockBot := mockTelegramBot{
+		mSend: func(_ tgbotapi.Chattable) (tgbotapi.Message, error) {
+			return tgbotapi.Message{}, nil
+		},
+		receivedMessages: []string{},
+	}
+
+	resultsURL, _ := url.Parse("https://example.com/jj")
+	p := New(&mockBot, 1, WithOutputDirectory(&resultsDir), WithResultsURL(resultsURL))
+	p.Handle(Message{Command: CommandStart, PlayerName: "Santana", FromID: 666, ChatID: 666})
+	p.Shutdown()
+
+	if p.State() != StateInit {
+		t.Errorf("State after shutdown = %s, want %s", p.State(), StateInit)
+	}
+
+	files, err := os.ReadDir(resultsDir)
+	if err != nil {
+		t.Fatalf("Failed to read results directory: %v", err)
+	}
+	// The HTML results come with a JSON copy
+	if len(files) != 2 || !strings.HasPrefix(files[0].Name(), "jukebox_jury_results_") ||
+		strings.TrimSuffix(files[0].Name(), ".html")+".json" != files[1].Name() {
+		t.Fatalf("Expected a single results file and its JSON, got %v", files)
+	}
+	results, err := os.ReadFile(filepath.Join(resultsDir, files[0].Name()))
+	if err != nil {
+		t.Fatalf("Failed to read results: %v", err)
+	}
+	if !strings.Contains(string(results), "The game was interrupted") {
+		t.Errorf("Results are not marked as partial:\n%s", results)
+	}
+	results, err = os.ReadFile(filepath.Join(resultsDir, files[1].Name()))
+	if err != nil || !strings.Contains(string(results), `"interrupted": true`) {
+		t.Errorf("JSON results are not marked as partial: %v\n%s", err, results)
+	}
+
+	expected := "Partial results are available in https://example.com/jj/" + files[0].Name()
+	if !slices.Contains(mockBot.receivedMessages, expected) {
+		t.Errorf("Message %q not sent, got:\n%s", expected, strings.Join(mockBot.receivedMessages, "\n"))
+	}
+}
+
+func TestResultsInWorkingDirectory(t *testing.T) {
+	workDir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Failed to get working directory: %v", err)
+	}
+	if err = os.Chdir(workDir); err != nil {
+		t.Fatalf("Failed to change working directory: %v", err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+	// Results must not pass through the temporary directory, which may
+	// be on another file system
+	t.Setenv("TMPDIR", filepath.Join(workDir, "missing"))
+
+	mockBot := mockTelegramBot{
+		mSend: func(_ tgbotapi.Chattable) (tgbotapi.Message, error) {
+			return tgbotapi.Message{}, nil
+		},
+		receivedMessages: []string{},
+	}
+	resultsDir := ""
+	p := New(&mockBot, 1, WithOutputDirectory(&resultsDir))
+	p.Handle(Message{Command: CommandStart, PlayerName: "Santana", FromID: 666, ChatID: 666})
+	p.Shutdown()
+
+	results, err := filepath.Glob(filepath.Join(workDir, "jukebox_jury_results_*"))
+	if err != nil || len(results) != 2 {
+		t.Fatalf("Expected the results and their JSON in the working directory, got %v: %v", results, err)
+	}
+	if slices.Contains(mockBot.receivedMessages, "Failed to save the results") {
+		t.Errorf("Saving the results failed:\n%s", strings.Join(mockBot.receivedMessages, "\n"))
+	}
+}
@@ -0,0 +1,615 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Language is the language of the bot's replies and commands in a chat.
+type Language string
+
+const (
+	LanguageEnglish Language = "en"
+	LanguageFinnish Language = "fi"
+)
+
+// ParseLanguage parses the language from its configuration value.
+func ParseLanguage(lang string) (Language, error) {
+	switch Language(strings.ToLower(lang)) {
+	case LanguageEnglish:
+		return LanguageEnglish, nil
+	case LanguageFinnish:
+		return LanguageFinnish, nil
+	}
+	return LanguageEnglish, fmt.Errorf("unknown language %q, expected en or fi", lang)
+}
+
+// WithLanguage sets the language of the replies. Commands are accepted in
+// the language besides the original Finnish ones, which the replies show.
+func WithLanguage(lang Language) PlayOption {
+	return func(p *Play) {
+		p.language = lang
+	}
+}
+
+// commandAliases lists the words for each command in the language. The
+// first Finnish alias is the one shown to users in every language.
+var commandAliases = map[Language]map[string][]string{
+	LanguageFinnish: {
+		CommandStart:    {"aloita"},
+		CommandStop:     {"lopeta"},
+		CommandContinue: {"jatka"},
+		CommandJoin:     {"liity"},
+		CommandKick:     {"potki"},
+		CommandSkip:     {"ohita"},
+		CommandHelp:     {"apua"},
+		CommandStatus:   {"tila"},
+		CommandPresent:  {"esitä", "esitys"},
+		CommandReview:   {"arvioi", "arvio", "arvostele"},
+		CommandComment:  {"kommentoi", "kommentti"},
+		CommandTour:     {"turnaus"},
+		CommandVote:     {"äänestä", "ääni"},
+	},
+	LanguageEnglish: {
+		CommandStart:    {"start"},
+		CommandStop:     {"stop"},
+		CommandContinue: {"continue"},
+		CommandJoin:     {"join"},
+		CommandKick:     {"kick"},
+		CommandSkip:     {"skip"},
+		CommandHelp:     {"help"},
+		CommandStatus:   {"status"},
+		CommandPresent:  {"present"},
+		CommandReview:   {"review"},
+		CommandComment:  {"comment"},
+		CommandTour:     {"tournament"},
+		CommandVote:     {"vote"},
+	},
+}
+
+// commandsFor maps the accepted command words in the language and the
+// custom aliases to the commands.
+func commandsFor(lang Language, custom map[string][]string) map[string]string {
+	commands := map[string]string{}
+	for _, l := range []Language{LanguageFinnish, lang} {
+		for command, aliases := range commandAliases[l] {
+			for _, alias := range aliases {
+				commands[alias] = command
+			}
+		}
+	}
+	for command, aliases := range custom {
+		for _, alias := range aliases {
+			commands[alias] = command
+		}
+	}
+	return commands
+}
+
+// resolveCommand returns the command the word stands for. Unknown words
+// are returned as is.
+func (p *Play) resolveCommand(word string) string {
+	if command, ok := p.commands[strings.ToLower(word)]; ok {
+		return command
+	}
+	return word
+}
+
+// commandName returns the word shown to users for the command. The
+// original Finnish commands are shown whatever the language of the replies.
+func (p *Play) commandName(command string) string {
+	if aliases := commandAliases[LanguageFinnish][command]; len(aliases) > 0 {
+		return aliases[0]
+	}
+	return command
+}
+
+// messageID identifies a user-facing text in the message catalog.
+type messageID string
+
+const (
+	msgNoGameToStop       messageID = "no_game_to_stop"
+	msgNoGameRunning      messageID = "no_game_running"
+	msgGameStarted        messageID = "game_started"
+	msgAlreadyInGame      messageID = "already_in_game"
+	msgJoined             messageID = "joined"
+	msgNoSongToSkip       messageID = "no_song_to_skip"
+	msgContinuing         messageID = "continuing"
+	msgHowToAddSong       messageID = "how_to_add_song"
+	msgHowToReview        messageID = "how_to_review"
+	msgWrongCommand       messageID = "wrong_command"
+	msgNoCommand          messageID = "no_command"
+	msgNoSender           messageID = "no_sender"
+	msgConfused           messageID = "confused"
+	msgConfusedTwice      messageID = "confused_twice"
+	msgSongAdded          messageID = "song_added"
+	msgAllSongsSubmitted  messageID = "all_songs_submitted"
+	msgNextSong           messageID = "next_song"
+	msgOwnSong            messageID = "own_song"
+	msgAlreadyReviewed    messageID = "already_reviewed"
+	msgJurorReviewed      messageID = "juror_reviewed"
+	msgPanelistReviewed   messageID = "panelist_reviewed"
+	msgAllReviewed        messageID = "all_reviewed"
+	msgAudienceReviewer   messageID = "audience_reviewer"
+	msgReview             messageID = "review"
+	msgFinalScore         messageID = "final_score"
+	msgFinalAudienceScore messageID = "final_audience_score"
+	msgSkipped            messageID = "skipped"
+	msgNoSuchPanelist     messageID = "no_such_panelist"
+	msgKicked             messageID = "kicked"
+	msgNoPanelistsLeft    messageID = "no_panelists_left"
+	msgGameStopped        messageID = "game_stopped"
+	msgGameOver           messageID = "game_over"
+	msgWinner             messageID = "winner"
+	msgEndingGame         messageID = "ending_game"
+	msgShutdown           messageID = "shutdown"
+	msgRenderFailed       messageID = "render_failed"
+	msgSaveFailed         messageID = "save_failed"
+	msgPartialResults     messageID = "partial_results"
+	msgResults            messageID = "results"
+	msgSongAlreadyAdded   messageID = "song_already_added"
+	msgNotInGame          messageID = "not_in_game"
+	msgMalformedSong      messageID = "malformed_song"
+	msgConfirmShare       messageID = "confirm_share"
+	msgButtonPresent      messageID = "button_present"
+	msgButtonCancel       messageID = "button_cancel"
+	msgSharePresented     messageID = "share_presented"
+	msgShareCancelled     messageID = "share_cancelled"
+	msgNothingToConfirm   messageID = "nothing_to_confirm"
+	msgScoreboardTitle    messageID = "scoreboard_title"
+	msgScoreboardEnded    messageID = "scoreboard_ended"
+	msgScoreboardPanel    messageID = "scoreboard_panel"
+	msgScoreboardRank     messageID = "scoreboard_ranking"
+	msgRatingMissing      messageID = "rating_missing"
+	msgRatingNotLast      messageID = "rating_not_last"
+	msgPollQuestion       messageID = "poll_question"
+	msgGameAlreadyStarted messageID = "game_already_started"
+	msgJoinedAudience     messageID = "joined_audience"
+	msgNotAllowed         messageID = "not_allowed"
+	msgRoleGameStarter    messageID = "role_game_starter"
+	msgRoleBotAdmins      messageID = "role_bot_admins"
+	msgRoleChatAdmins     messageID = "role_chat_admins"
+	msgRoleNobody         messageID = "role_nobody"
+	msgOr                 messageID = "or"
+	msgActionContinue     messageID = "action_continue"
+	msgActionStop         messageID = "action_stop"
+	msgActionKick         messageID = "action_kick"
+	msgActionSkip         messageID = "action_skip"
+	msgStateNotRunning    messageID = "state_not_running"
+	msgStateJoining       messageID = "state_joining"
+	msgStateAddingSongs   messageID = "state_adding_songs"
+	msgStateReviewing     messageID = "state_reviewing"
+	msgStateOther         messageID = "state_other"
+	msgAvailableCommands  messageID = "available_commands"
+	msgHelpStart          messageID = "help_start"
+	msgHelpJoin           messageID = "help_join"
+	msgHelpLateJoin       messageID = "help_late_join"
+	msgHelpContinue       messageID = "help_continue"
+	msgHelpPresent        messageID = "help_present"
+	msgHelpReview         messageID = "help_review"
+	msgHelpKick           messageID = "help_kick"
+	msgHelpSkip           messageID = "help_skip"
+	msgHelpStop           messageID = "help_stop"
+	msgHelpHelp           messageID = "help_help"
+	msgHelpStatus         messageID = "help_status"
+	msgUsagePresent       messageID = "usage_present"
+	msgUsageReview        messageID = "usage_review"
+	msgUsageKick          messageID = "usage_kick"
+	msgStatusPanelists    messageID = "status_panelists"
+	msgStatusSubmitted    messageID = "status_submitted"
+	msgStatusMissingSongs messageID = "status_missing_songs"
+	msgStatusCurrentSong  messageID = "status_current_song"
+	msgStatusMissingRevs  messageID = "status_missing_reviews"
+	msgStatusJurors       messageID = "status_jurors"
+	msgStatusRunningFor   messageID = "status_running_for"
+	msgNone               messageID = "none"
+	msgHowToComment       messageID = "how_to_comment"
+	msgWaitForComment     messageID = "wait_for_commentary"
+	msgNotTheHost         messageID = "not_the_host"
+	msgCommentMissing     messageID = "commentary_missing"
+	msgCommentary         messageID = "commentary"
+	msgVoiceCommentary    messageID = "voice_commentary"
+	msgNoCommentary       messageID = "no_commentary"
+	msgStateCommenting    messageID = "state_commenting"
+	msgHelpComment        messageID = "help_comment"
+	msgHelpSkipComment    messageID = "help_skip_commentary"
+	msgUsageComment       messageID = "usage_comment"
+	msgVoiceRatingMissing messageID = "voice_rating_missing"
+	msgVoiceReview        messageID = "voice_review"
+	msgBestJudges         messageID = "best_judges"
+	msgConsensusAverage   messageID = "consensus_average"
+	msgConsensusMedian    messageID = "consensus_median"
+	msgHowToPredict       messageID = "how_to_predict"
+	msgFinalPrediction    messageID = "final_prediction"
+	msgBestPredictions    messageID = "best_predictions"
+	msgPredictionRank     messageID = "prediction_rank"
+	msgPredictionTooHigh  messageID = "prediction_too_high"
+	msgTournamentStarted  messageID = "tournament_started"
+	msgNoTournament       messageID = "no_tournament"
+	msgTooFewSongs        messageID = "too_few_songs"
+	msgMatch              messageID = "match"
+	msgHowToVote          messageID = "how_to_vote"
+	msgOwnMatch           messageID = "own_match"
+	msgVoted              messageID = "voted"
+	msgVoteChanged        messageID = "vote_changed"
+	msgMatchWinner        messageID = "match_winner"
+	msgRoundFinished      messageID = "round_finished"
+	msgChampion           messageID = "champion"
+	msgBracket            messageID = "bracket"
+	msgStateVoting        messageID = "state_voting"
+	msgHelpTournament     messageID = "help_tournament"
+	msgHelpVote           messageID = "help_vote"
+	msgHelpCloseMatch     messageID = "help_close_match"
+	msgUsageVote          messageID = "usage_vote"
+	msgStatusMatch        messageID = "status_match"
+	msgStatusVotes        messageID = "status_votes"
+	msgEliminated         messageID = "eliminated"
+	msgEliminationTie     messageID = "elimination_tie"
+	msgEliminationDraw    messageID = "elimination_draw"
+	msgEliminationRound   messageID = "elimination_round"
+	msgEliminatedSong     messageID = "eliminated_song"
+	msgStatusEliminated   messageID = "status_eliminated"
+	msgGameStartedTeams   messageID = "game_started_teams"
+	msgJoinedTeam         messageID = "joined_team"
+	msgTeamChanged        messageID = "team_changed"
+	msgSingleTeam         messageID = "single_team"
+	msgOwnTeamSong        messageID = "own_team_song"
+	msgTeamScores         messageID = "team_scores"
+	msgTeamRank           messageID = "team_rank"
+	msgHelpJoinTeam       messageID = "help_join_team"
+	msgUsageJoinTeam      messageID = "usage_join_team"
+)
+
+// messages are the fmt formats of the texts in each language. The formats
+// are plain text, the formatting comes from the rich text arguments.
+var messages = map[Language]map[messageID]string{
+	LanguageEnglish: {
+		msgNoGameToStop:  "There is no game to stop",
+		msgNoGameRunning: "No game running, start one with: %s %s",
+		msgGameStarted:   "User %s started a new game, join by using command: %s %s",
+		msgAlreadyInGame: "You are already in the game",
+		msgJoined:        "User %s joined the game",
+		msgNoSongToSkip:  "There is no song to skip yet",
+		msgContinuing:    "User %s wants to proceed, continuing...",
+		msgHowToAddSong: "Add song with the following command and format in private chat with the bot: %s %s " +
+			"description here https://link-as-last-item",
+		msgHowToReview: "Add review similar way (max score is %[3]d, only integers): " +
+			"%[1]s %[2]s description here 0/%[3]d",
+		msgWrongCommand:       "Aww cute, but it's a wrong command.",
+		msgNoCommand:          "The command is missing, see %s %s",
+		msgNoSender:           "Anonymous messages can't take part in the game, send the command as yourself",
+		msgConfused:           "Me confused, please try again",
+		msgConfusedTwice:      "Me confused two times, please try again",
+		msgSongAdded:          "Panelist %s added a song",
+		msgAllSongsSubmitted:  "All songs submitted, continuing...",
+		msgNextSong:           "The next song comes from the panelist %s: %s",
+		msgOwnSong:            "You naughty. It's not possible to review own songs",
+		msgAlreadyReviewed:    "You have already reviewed this song",
+		msgJurorReviewed:      "Audience juror %s reviewed the song",
+		msgPanelistReviewed:   "Panelist %s reviewed the song",
+		msgAllReviewed:        "Everybody has reviewed the song, continuing...",
+		msgAudienceReviewer:   "%s (audience)",
+		msgReview:             "%s wrote: %s. The song rating was: %d/%d",
+		msgFinalScore:         "Eventually the song %s ended up catching %0.2f points",
+		msgFinalAudienceScore: " and the audience gave it %0.2f points",
+		msgSkipped:            "%s skipped the song from %s",
+		msgNoSuchPanelist:     "There is no panelist named %q in the game",
+		msgKicked:             "Panelist %s was kicked out of the game by %s",
+		msgNoPanelistsLeft:    "No panelists left, ending the game",
+		msgGameStopped:        "The game was stopped by %s",
+		msgGameOver:           "The game is over, here are the results",
+		msgWinner: "Game has ended. The winner song came from %s and was %s " +
+			"with %.2f average score",
+		msgEndingGame:       "Ending the game",
+		msgShutdown:         "The bot is going down, the game was interrupted",
+		msgRenderFailed:     "Failed to render the results",
+		msgSaveFailed:       "Failed to save the results",
+		msgPartialResults:   "Partial results are available in %s",
+		msgResults:          "Results are available in %s",
+		msgSongAlreadyAdded: "Song already added",
+		msgNotInGame:        "You are not in the game, join the next one",
+		msgMalformedSong:    "Song given in the malformed form",
+		msgConfirmShare:     "Present this song? Description: %s, URL: %s",
+		msgButtonPresent:    "Present",
+		msgButtonCancel:     "Cancel",
+		msgSharePresented:   "Song presented",
+		msgShareCancelled:   "Song wasn't presented",
+		msgNothingToConfirm: "There is no song waiting for confirmation",
+		msgScoreboardTitle:  "Jukebox Jury: %s",
+		msgScoreboardEnded:  "Jukebox Jury: Game has ended",
+		msgScoreboardPanel:  "Panelists:",
+		msgScoreboardRank:   "Ranking:",
+		msgRatingMissing: "Did you forgot to give the points? " +
+			"Those should be in 0/%d format and as a last item.",
+		msgRatingNotLast:      "Check that the scoring is last item and separated with a space: ... 0/%d",
+		msgPollQuestion:       "Audience, how many points does the song from %s deserve?",
+		msgGameAlreadyStarted: "The game has already started, join the next one",
+		msgJoinedAudience:     "User %s joined the audience jury",
+		msgNotAllowed:         "Sorry, only %s can %s",
+		msgRoleGameStarter:    "the game starter",
+		msgRoleBotAdmins:      "bot admins",
+		msgRoleChatAdmins:     "chat admins",
+		msgRoleNobody:         "nobody",
+		msgOr:                 "or",
+		msgActionContinue:     "continue the game",
+		msgActionStop:         "stop the game",
+		msgActionKick:         "kick panelists",
+		msgActionSkip:         "skip songs",
+		msgStateNotRunning:    "Game is not running",
+		msgStateJoining:       "Game is waiting for panelists to join",
+		msgStateAddingSongs:   "Game is waiting for songs",
+		msgStateReviewing:     "Game is reviewing songs",
+		msgStateOther:         "Game is in state %s",
+		msgAvailableCommands:  "%s. Available commands:",
+		msgHelpStart:          "start a new game",
+		msgHelpJoin:           "join the game",
+		msgHelpLateJoin:       "join the audience jury",
+		msgHelpContinue:       "stop joining and start adding songs",
+		msgHelpPresent:        "add your song in a private chat with the bot",
+		msgHelpReview:         "review the current song",
+		msgHelpKick:           "kick a panelist out of the game",
+		msgHelpSkip:           "skip the current song",
+		msgHelpStop:           "stop the game",
+		msgHelpHelp:           "show this help",
+		msgHelpStatus:         "show the game status",
+		msgUsagePresent:       "<description> <link>",
+		msgUsageReview:        "<review> <0-%[1]d>/%[1]d",
+		msgUsageKick:          "<name>",
+		msgStatusPanelists:    "Panelists: %s",
+		msgStatusSubmitted:    "Songs submitted: %s",
+		msgStatusMissingSongs: "Songs missing: %s",
+		msgStatusCurrentSong:  "Current song from: %s",
+		msgStatusMissingRevs:  "Reviews missing: %s",
+		msgStatusJurors:       "Audience jurors: %d",
+		msgStatusRunningFor:   "Game has been running for %s",
+		msgNone:               "none",
+		msgHowToComment: "%s, tell why you chose the song with: %s %s <commentary>, " +
+			"or reply with it to a voice note",
+		msgWaitForComment:  "Wait for %s to comment on the song first",
+		msgNotTheHost:      "Only the panelist who chose the song can comment on it",
+		msgCommentMissing:  "Write the commentary after the command, or reply with it to a voice note",
+		msgCommentary:      "%s on why they chose the song: %s",
+		msgVoiceCommentary: "%s on why they chose the song",
+		msgNoCommentary:    "No commentary from %s, review the song",
+		msgStateCommenting: "Game is waiting for the commentary of the song's panelist",
+		msgHelpComment:     "tell why you chose your song",
+		msgHelpSkipComment: "continue without the commentary",
+		msgUsageComment:    "<commentary>",
+		msgVoiceRatingMissing: "Give the rating of a voice review as its caption, or reply to it with: " +
+			"%s %s 0/%d",
+		msgVoiceReview:      "(voice review)",
+		msgBestJudges:       "Best judges by how close their ratings were to the %s:",
+		msgConsensusAverage: "average scores",
+		msgConsensusMedian:  "median ratings",
+		msgHowToPredict: "Predict the average score of your song by ending the command with it, " +
+			"e.g. %s %s description https://link 7.5/%d. The prediction is revealed with the reviews",
+		msgFinalPrediction:   ", %s predicted %0.2f",
+		msgBestPredictions:   "Closest predictions of the own song's score:",
+		msgPredictionRank:    "%s predicted %0.2f and got %0.2f",
+		msgPredictionTooHigh: "The predicted score is higher than the highest rating",
+		msgTournamentStarted: "%s started a tournament night, %d songs are competing",
+		msgNoTournament:      "The tournament couldn't be started",
+		msgTooFewSongs:       "A tournament needs at least two songs from the previous games",
+		msgMatch: "Tournament round %d:\n1. %s from %s\n2. %s from %s\n" +
+			"Vote with %s or %s, the match is closed with %s",
+		msgHowToVote:   "Vote with %s or %s",
+		msgOwnMatch:    "You can't vote in the match of your own song",
+		msgVoted:       "%s voted",
+		msgVoteChanged: "Your vote was changed",
+		msgMatchWinner: "%s from %s advances with %d-%d votes",
+		msgRoundFinished: "Round %d of the tournament is finished, " +
+			"the tournament continues on the next game night",
+		msgChampion:       "The tournament is over, the champion is %s from %s!",
+		msgBracket:        "Tournament bracket: %s",
+		msgStateVoting:    "Voting in a tournament match",
+		msgHelpTournament: "start a tournament night between the songs of the previous games",
+		msgHelpVote:       "vote for the better song of the match",
+		msgHelpCloseMatch: "close the match and count the votes",
+		msgUsageVote:      "1|2",
+		msgStatusMatch:    "Match: %s vs. %s",
+		msgStatusVotes:    "Votes: %d",
+		msgEliminated: "%s is eliminated, %s got the lowest score %0.2f. " +
+			"%d panelists remain, the eliminated ones keep reviewing",
+		msgEliminationTie: "%s share the lowest score %0.2f, the scores of the earlier rounds break the tie",
+		msgEliminationDraw: "%s share the lowest score %0.2f and tie on the earlier rounds too, " +
+			"a draw breaks the tie",
+		msgEliminationRound: "Elimination round %d: %s, add your next songs with: %s %s " +
+			"description here https://link-as-last-item",
+		msgEliminatedSong:   "You have been eliminated, but you can still review the songs",
+		msgStatusEliminated: "Eliminated: %s",
+		msgGameStartedTeams: "User %s started a new team game, join your team by using command: " +
+			"%s %s <team>",
+		msgJoinedTeam:    "User %s joined the game in team %s",
+		msgTeamChanged:   "%s switched to team %s",
+		msgSingleTeam:    "Every panelist is in the same team, so nobody could review the songs",
+		msgOwnTeamSong:   "You can't review the songs of your own team",
+		msgTeamScores:    "Team scores by the average score of their songs:",
+		msgTeamRank:      "%s %0.2f (%s)",
+		msgHelpJoinTeam:  "join the game in a team, or switch the team",
+		msgUsageJoinTeam: "<team>",
+	},
+	LanguageFinnish: {
+		msgNoGameToStop:  "Lopetettavaa peliä ei ole",
+		msgNoGameRunning: "Peliä ei ole käynnissä, aloita uusi komennolla: %s %s",
+		msgGameStarted:   "Käyttäjä %s aloitti uuden pelin, liity komennolla: %s %s",
+		msgAlreadyInGame: "Olet jo mukana pelissä",
+		msgJoined:        "Käyttäjä %s liittyi peliin",
+		msgNoSongToSkip:  "Ohitettavaa kappaletta ei vielä ole",
+		msgContinuing:    "Käyttäjä %s haluaa jatkaa, jatketaan...",
+		msgHowToAddSong: "Lisää kappale yksityisviestillä botille seuraavalla komennolla ja muodolla: " +
+			"%s %s kuvaus tähän https://linkki-viimeisenä",
+		msgHowToReview: "Arvioi samalla tavalla (enintään %[3]d pistettä, vain kokonaislukuja): " +
+			"%[1]s %[2]s arvio tähän 0/%[3]d",
+		msgWrongCommand: "Söpöä, mutta väärä komento.",
+		msgNoCommand:    "Komento puuttuu, katso %s %s",
+		msgNoSender: "Nimettömät viestit eivät voi osallistua peliin, " +
+			"lähetä komento omissa nimissäsi",
+		msgConfused:           "Minä hämmentynyt, yritä uudelleen",
+		msgConfusedTwice:      "Minä kahdesti hämmentynyt, yritä uudelleen",
+		msgSongAdded:          "Panelisti %s lisäsi kappaleen",
+		msgAllSongsSubmitted:  "Kaikki kappaleet lisätty, jatketaan...",
+		msgNextSong:           "Seuraavan kappaleen valitsi panelisti %s: %s",
+		msgOwnSong:            "Tuhma. Omaa kappaletta ei voi arvioida",
+		msgAlreadyReviewed:    "Olet jo arvioinut tämän kappaleen",
+		msgJurorReviewed:      "Yleisöraatilainen %s arvioi kappaleen",
+		msgPanelistReviewed:   "Panelisti %s arvioi kappaleen",
+		msgAllReviewed:        "Kaikki ovat arvioineet kappaleen, jatketaan...",
+		msgAudienceReviewer:   "%s (yleisö)",
+		msgReview:             "%s kirjoitti: %s. Kappaleen arvosana: %d/%d",
+		msgFinalScore:         "Lopulta kappale %s keräsi %0.2f pistettä",
+		msgFinalAudienceScore: " ja yleisö antoi sille %0.2f pistettä",
+		msgSkipped:            "%s ohitti panelistin %s kappaleen",
+		msgNoSuchPanelist:     "Pelissä ei ole panelistia nimeltä %q",
+		msgKicked:             "%[2]s potkaisi panelistin %[1]s pois pelistä",
+		msgNoPanelistsLeft:    "Panelisteja ei ole jäljellä, peli päättyy",
+		msgGameStopped:        "%s lopetti pelin",
+		msgGameOver:           "Peli on ohi, tässä tulokset",
+		msgWinner: "Peli on päättynyt. Voittajakappaleen valitsi %s " +
+			"ja se oli %s keskiarvolla %.2f",
+		msgEndingGame:         "Peli päättyy",
+		msgShutdown:           "Botti sammuu, peli keskeytettiin",
+		msgRenderFailed:       "Tulosten muodostaminen epäonnistui",
+		msgSaveFailed:         "Tulosten tallentaminen epäonnistui",
+		msgPartialResults:     "Osittaiset tulokset löytyvät osoitteesta %s",
+		msgResults:            "Tulokset löytyvät osoitteesta %s",
+		msgSongAlreadyAdded:   "Kappale on jo lisätty",
+		msgNotInGame:          "Et ole mukana pelissä, liity seuraavaan",
+		msgMalformedSong:      "Kappale annettiin väärässä muodossa",
+		msgConfirmShare:       "Esitetäänkö tämä kappale? Kuvaus: %s, linkki: %s",
+		msgButtonPresent:      "Esitä",
+		msgButtonCancel:       "Peru",
+		msgSharePresented:     "Kappale esitetty",
+		msgShareCancelled:     "Kappaletta ei esitetty",
+		msgNothingToConfirm:   "Vahvistusta odottavaa kappaletta ei ole",
+		msgScoreboardTitle:    "Levyraati: %s",
+		msgScoreboardEnded:    "Levyraati: Peli on päättynyt",
+		msgScoreboardPanel:    "Panelistit:",
+		msgScoreboardRank:     "Sijoitukset:",
+		msgRatingMissing:      "Unohditko antaa pisteet? Ne annetaan muodossa 0/%d viimeisenä.",
+		msgRatingNotLast:      "Tarkista, että pisteet ovat viimeisenä välilyönnillä erotettuna: ... 0/%d",
+		msgPollQuestion:       "Yleisö, montako pistettä panelistin %s kappale ansaitsee?",
+		msgGameAlreadyStarted: "Peli on jo alkanut, liity seuraavaan",
+		msgJoinedAudience:     "Käyttäjä %s liittyi yleisöraatiin",
+		msgNotAllowed:         "Valitettavasti vain %s voi %s",
+		msgRoleGameStarter:    "pelin aloittaja",
+		msgRoleBotAdmins:      "botin ylläpitäjät",
+		msgRoleChatAdmins:     "chatin ylläpitäjät",
+		msgRoleNobody:         "ei kukaan",
+		msgOr:                 "tai",
+		msgActionContinue:     "jatkaa peliä",
+		msgActionStop:         "lopettaa pelin",
+		msgActionKick:         "potkia panelisteja",
+		msgActionSkip:         "ohittaa kappaleita",
+		msgStateNotRunning:    "Peli ei ole käynnissä",
+		msgStateJoining:       "Peli odottaa panelisteja",
+		msgStateAddingSongs:   "Peli odottaa kappaleita",
+		msgStateReviewing:     "Kappaleita arvioidaan",
+		msgStateOther:         "Peli on tilassa %s",
+		msgAvailableCommands:  "%s. Käytettävissä olevat komennot:",
+		msgHelpStart:          "aloita uusi peli",
+		msgHelpJoin:           "liity peliin",
+		msgHelpLateJoin:       "liity yleisöraatiin",
+		msgHelpContinue:       "lopeta liittyminen ja siirry kappaleiden lisäämiseen",
+		msgHelpPresent:        "lisää kappaleesi yksityisviestillä botille",
+		msgHelpReview:         "arvioi nykyinen kappale",
+		msgHelpKick:           "potkaise panelisti pois pelistä",
+		msgHelpSkip:           "ohita nykyinen kappale",
+		msgHelpStop:           "lopeta peli",
+		msgHelpHelp:           "näytä tämä ohje",
+		msgHelpStatus:         "näytä pelin tilanne",
+		msgUsagePresent:       "<kuvaus> <linkki>",
+		msgUsageReview:        "<arvio> <0-%[1]d>/%[1]d",
+		msgUsageKick:          "<nimi>",
+		msgStatusPanelists:    "Panelistit: %s",
+		msgStatusSubmitted:    "Kappaleen lisänneet: %s",
+		msgStatusMissingSongs: "Kappale puuttuu: %s",
+		msgStatusCurrentSong:  "Nykyisen kappaleen valitsi: %s",
+		msgStatusMissingRevs:  "Arvio puuttuu: %s",
+		msgStatusJurors:       "Yleisöraatilaisia: %d",
+		msgStatusRunningFor:   "Peli on ollut käynnissä %s",
+		msgNone:               "ei kukaan",
+		msgHowToComment: "%s, kerro miksi valitsit kappaleen komennolla: %s %s <kommentti>, " +
+			"tai vastaa sillä ääniviestiin",
+		msgWaitForComment:  "Odota ensin panelistin %s kommenttia kappaleeseen",
+		msgNotTheHost:      "Vain kappaleen valinnut panelisti voi kommentoida sitä",
+		msgCommentMissing:  "Kirjoita kommentti komennon perään, tai vastaa komennolla ääniviestiin",
+		msgCommentary:      "%s kertoo valinnastaan: %s",
+		msgVoiceCommentary: "%s kertoo valinnastaan",
+		msgNoCommentary:    "Ei kommenttia panelistilta %s, arvioikaa kappale",
+		msgStateCommenting: "Peli odottaa kappaleen valinneen panelistin kommenttia",
+		msgHelpComment:     "kerro miksi valitsit kappaleesi",
+		msgHelpSkipComment: "jatka ilman kommenttia",
+		msgUsageComment:    "<kommentti>",
+		msgVoiceRatingMissing: "Anna ääniarvion pisteet sen kuvatekstinä tai vastaa siihen: " +
+			"%s %s 0/%d",
+		msgVoiceReview:      "(ääniarvio)",
+		msgBestJudges:       "Parhaat tuomarit sen mukaan, kuinka lähelle %s he osuivat:",
+		msgConsensusAverage: "keskiarvoja",
+		msgConsensusMedian:  "mediaaniarvosanoja",
+		msgHowToPredict: "Ennusta kappaleesi keskiarvo lopettamalla komento siihen, " +
+			"esim. %s %s kuvaus https://linkki 7.5/%d. Ennuste paljastetaan arvioiden kanssa",
+		msgFinalPrediction:   ", %s ennusti %0.2f",
+		msgBestPredictions:   "Osuvimmat ennusteet oman kappaleen pisteistä:",
+		msgPredictionRank:    "%s ennusti %0.2f ja sai %0.2f",
+		msgPredictionTooHigh: "Ennustettu pistemäärä on suurempi kuin suurin arvosana",
+		msgTournamentStarted: "%s aloitti turnausillan, kilpailemassa on %d kappaletta",
+		msgNoTournament:      "Turnausta ei voitu aloittaa",
+		msgTooFewSongs:       "Turnaukseen tarvitaan vähintään kaksi kappaletta aiemmista peleistä",
+		msgMatch: "Turnauksen kierros %d:\n1. %s valitsijalta %s\n2. %s valitsijalta %s\n" +
+			"Äänestä komennolla %s tai %s, ottelu päätetään komennolla %s",
+		msgHowToVote:      "Äänestä komennolla %s tai %s",
+		msgOwnMatch:       "Et voi äänestää oman kappaleesi ottelussa",
+		msgVoted:          "%s äänesti",
+		msgVoteChanged:    "Äänesi vaihdettiin",
+		msgMatchWinner:    "%s valitsijalta %s jatkaa äänin %d-%d",
+		msgRoundFinished:  "Turnauksen kierros %d on pelattu, turnaus jatkuu seuraavana peli-iltana",
+		msgChampion:       "Turnaus on päättynyt, mestari on %s valitsijalta %s!",
+		msgBracket:        "Turnauskaavio: %s",
+		msgStateVoting:    "Turnausottelua äänestetään",
+		msgHelpTournament: "aloita turnausilta aiempien pelien kappaleiden kesken",
+		msgHelpVote:       "äänestä ottelun parempaa kappaletta",
+		msgHelpCloseMatch: "päätä ottelu ja laske äänet",
+		msgUsageVote:      "1|2",
+		msgStatusMatch:    "Ottelu: %s vastaan %s",
+		msgStatusVotes:    "Ääniä: %d",
+		msgEliminated: "%s putoaa, %s sai pienimmät pisteet %0.2f. " +
+			"Jäljellä on %d panelistia, pudonneet arvioivat edelleen",
+		msgEliminationTie: "%s jakavat pienimmät pisteet %0.2f, " +
+			"aiempien kierrosten pisteet ratkaisevat",
+		msgEliminationDraw: "%s jakavat pienimmät pisteet %0.2f ja ovat tasoissa myös " +
+			"aiemmilla kierroksilla, arvonta ratkaisee",
+		msgEliminationRound: "Pudotuskierros %d: %s, lisätkää seuraavat kappaleet komennolla: %s %s " +
+			"kuvaus tähän https://linkki-viimeisenä",
+		msgEliminatedSong:   "Olet pudonnut, mutta voit edelleen arvioida kappaleita",
+		msgStatusEliminated: "Pudonneet: %s",
+		msgGameStartedTeams: "Käyttäjä %s aloitti uuden joukkuepelin, liity joukkueeseesi komennolla: " +
+			"%s %s <joukkue>",
+		msgJoinedTeam:  "Käyttäjä %s liittyi peliin joukkueeseen %s",
+		msgTeamChanged: "%s vaihtoi joukkueeseen %s",
+		msgSingleTeam: "Kaikki panelistit ovat samassa joukkueessa, " +
+			"joten kukaan ei voisi arvioida kappaleita",
+		msgOwnTeamSong:   "Et voi arvioida oman joukkueesi kappaleita",
+		msgTeamScores:    "Joukkueiden pisteet kappaleiden keskiarvon mukaan:",
+		msgTeamRank:      "%s %0.2f (%s)",
+		msgHelpJoinTeam:  "liity peliin joukkueessa tai vaihda joukkuetta",
+		msgUsageJoinTeam: "<joukkue>",
+	},
+}
+
+// tr formats the text in the language of the game. Texts missing from
+// the language fall back to English. Plain string arguments are escaped
+// while rich text arguments keep their formatting.
+func (p *Play) tr(id messageID, args ...any) richText {
+	format, ok := messages[p.language][id]
+	if !ok {
+		format = messages[LanguageEnglish][id]
+	}
+	escaped := make([]any, len(args))
+	for i, arg := range args {
+		if s, isString := arg.(string); isString {
+			arg = escape(s)
+		}
+		escaped[i] = arg
+	}
+	return richText(fmt.Sprintf(string(escape(format)), escaped...))
+}
@@ -0,0 +1,78 @@
+package game
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMessageCatalogsComplete(t *testing.T) {
+	for lang, catalog := range messages {
+		for id := range messages[LanguageEnglish] {
+			if _, ok := catalog[id]; !ok {
+				t.Errorf("Language %q is missing message %q", lang, id)
+			}
+		}
+		for id := range catalog {
+			if _, ok := messages[LanguageEnglish][id]; !ok {
+				t.Errorf("Language %q has message %q missing from English", lang, id)
+			}
+		}
+	}
+}
+
+func TestFinnishGame(t *testing.T) {
+	mockBot := mockTelegramBot{
+		mSend: func(_ tgbotapi.Chattable) (tgbotapi.Message, error) {
+			return tgbotapi.Message{}, nil
+		},
+		receivedMessages: []string{},
+	}
+
+	santana := &tgbotapi.User{ID: 666, UserName: "Santana"}
+	jesus := &tgbotapi.User{ID: 123, UserName: "Jesus"}
+	updates := []tgbotapi.Message{
+		testMessage(santana, "levyraati aloita"),
+		testMessage(jesus, "levyraati LIITY"),
+	}
+
+	p := New(&mockBot, 1, WithOutputDirectory(nil), WithLanguage(LanguageFinnish))
+	for i, update := range updates {
+		msg, err := ParseToMessage(tgbotapi.Update{Message: &update})
+		if err != nil {
+			t.Fatalf("Failed to parse %d %q: %#v", i, update.Text, err)
+		}
+		p.Handle(msg)
+	}
+
+	expected := []string{
+		"Käyttäjä <b>Santana</b> aloitti uuden pelin, liity komennolla: levyraati liity",
+		"Käyttäjä <b>Jesus</b> liittyi peliin",
+	}
+	if diff := cmp.Diff(expected, mockBot.receivedMessages); diff != "" {
+		t.Errorf("Messages mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestEnglishGameAcceptsFinnishCommands(t *testing.T) {
+	p := New(&mockTelegramBot{}, 1)
+	aliases := map[string]string{"liity": CommandJoin, "join": CommandJoin, "arvostele": CommandReview}
+	for word, want := range aliases {
+		if got := p.resolveCommand(word); got != want {
+			t.Errorf("resolveCommand(%q) = %q, want %q", word, got, want)
+		}
+	}
+	if got := p.commandName(CommandReview); got != "arvioi" {
+		t.Errorf("commandName(%q) = %q, want arvioi", CommandReview, got)
+	}
+}
+
+func TestParseLanguage(t *testing.T) {
+	if lang, err := ParseLanguage("FI"); err != nil || lang != LanguageFinnish {
+		t.Errorf("ParseLanguage(FI) = %q, %v, want %q", lang, err, LanguageFinnish)
+	}
+	if _, err := ParseLanguage("sv"); err == nil {
+		t.Error("ParseLanguage(sv) succeeded, want an error")
+	}
+}
@@ -0,0 +1,134 @@
+package game
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"weezel/jukeboxjury/internal/logger"
+)
+
+// JudgeScoring controls the best judge side game, which awards reviewers
+// points for rating the songs close to the consensus of the reviews.
+type JudgeScoring int
+
+const (
+	// JudgeScoringDisabled doesn't score the reviewers.
+	JudgeScoringDisabled JudgeScoring = iota
+	// JudgeScoringAverage compares the ratings to the song's average score.
+	JudgeScoringAverage
+	// JudgeScoringMedian compares the ratings to the median rating of the song.
+	JudgeScoringMedian
+)
+
+// ParseJudgeScoring parses the scoring from its configuration value. An
+// empty value disables the scoring.
+func ParseJudgeScoring(scoring string) (JudgeScoring, error) {
+	switch strings.ToLower(scoring) {
+	case "", "disabled":
+		return JudgeScoringDisabled, nil
+	case "average":
+		return JudgeScoringAverage, nil
+	case "median":
+		return JudgeScoringMedian, nil
+	}
+	return JudgeScoringDisabled, fmt.Errorf("unknown judge scoring %q, expected average or median", scoring)
+}
+
+// WithJudgeScoring awards the reviewers points for how close their ratings
+// were to the consensus, and ranks the best judges at the end of the game.
+func WithJudgeScoring(scoring JudgeScoring) PlayOption {
+	return func(p *Play) {
+		p.judgeScoring = scoring
+	}
+}
+
+// consensus returns the rating the reviews of the current song are compared
+// to. The consensus is counted from the same reviews as the song's score.
+func (p *Play) consensus() (float64, bool) {
+	ratings := []int{}
+	for _, r := range p.host.ReceivedReviews {
+		if r.Audience && p.audienceMode == AudienceSeparate {
+			continue
+		}
+		ratings = append(ratings, r.Rating)
+	}
+	if len(ratings) == 0 {
+		return 0, false
+	}
+	if p.judgeScoring == JudgeScoringAverage {
+		return p.host.Song.AverageScore, true
+	}
+
+	slices.Sort(ratings)
+	mid := len(ratings) / 2
+	if len(ratings)%2 == 1 {
+		return float64(ratings[mid]), true
+	}
+	return float64(ratings[mid-1]+ratings[mid]) / 2, true
+}
+
+// scoreJudges awards the reviewers of the current song. A rating equal to
+// the consensus is worth the highest rating in points, and every point of
+// difference takes a point off.
+func (p *Play) scoreJudges() {
+	if p.judgeScoring == JudgeScoringDisabled {
+		return
+	}
+	consensus, ok := p.consensus()
+	if !ok {
+		return
+	}
+
+	for _, r := range p.host.ReceivedReviews {
+		judge := p.findReviewer(r.uid)
+		if judge == nil {
+			continue // Kicked out of the game
+		}
+		judge.JudgeScore += float64(p.ratingMax) - math.Abs(float64(r.Rating)-consensus)
+		judge.judged = true
+	}
+	logger.Logger.Debug().
+		Float64("consensus", consensus).
+		Msgf("Scored the judges of the song %s", p.host.Song.URL)
+}
+
+// BestJudges ranks the reviewers by their judge scores, the best first.
+// Reviewers who haven't reviewed any revealed song are left out.
+func (p Play) BestJudges() []*Panelist {
+	judges := []*Panelist{}
+	for _, judge := range slices.Concat(p.Panelists, p.AudienceJurors) {
+		if judge.judged {
+			judges = append(judges, judge)
+		}
+	}
+	slices.SortStableFunc(judges, func(a, b *Panelist) int {
+		return cmp.Compare(b.JudgeScore, a.JudgeScore)
+	})
+	return judges
+}
+
+// announceBestJudges tells the channel who judged the songs best.
+func (p *Play) announceBestJudges() {
+	judges := p.BestJudges()
+	if len(judges) == 0 {
+		return
+	}
+
+	consensus := p.tr(msgConsensusAverage)
+	if p.judgeScoring == JudgeScoringMedian {
+		consensus = p.tr(msgConsensusMedian)
+	}
+	var sb strings.Builder
+	sb.WriteString(string(p.tr(msgBestJudges, consensus)))
+	for i, judge := range judges {
+		name := bold(judge.Name)
+		if judge.audience {
+			name = p.tr(msgAudienceReviewer, name)
+		}
+		fmt.Fprintf(&sb, "\n%d. %s %.2f", i+1, name, judge.JudgeScore)
+	}
+	p.sendMessageToChannel(richText(sb.String()))
+}
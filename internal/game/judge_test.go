@@ -0,0 +1,125 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestBestJudges(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		audience AudienceMode
+		scoring  JudgeScoring
+		ranking  string // Sent at the end of the game, empty when none
+		results  []string
+	}{
+		{
+			name:     "Median of all the ratings",
+			audience: AudienceCounted,
+			scoring:  JudgeScoringMedian,
+			ranking: "Best judges by how close their ratings were to the median ratings:\n" +
+				"1. <b>Maria</b> (audience) 26.00\n2. <b>Pjotr</b> 19.00\n" +
+				"3. <b>Jesus</b> 18.00\n4. <b>Santana</b> 15.00",
+			results: []string{"<li>Maria: 26.00 points</li>", "<li>Santana: 15.00 points</li>"},
+		},
+		{
+			name:     "Average of the panel",
+			audience: AudienceSeparate,
+			scoring:  JudgeScoringAverage,
+			ranking: "Best judges by how close their ratings were to the average scores:\n" +
+				"1. <b>Maria</b> (audience) 23.00\n2. <b>Jesus</b> 19.00\n" +
+				"3. <b>Santana</b> 17.00\n4. <b>Pjotr</b> 16.00",
+			results: []string{"<li>Maria: 23.00 points</li>", "<li>Pjotr: 16.00 points</li>"},
+		},
+		{
+			name:     "Disabled",
+			audience: AudienceCounted,
+			scoring:  JudgeScoringDisabled,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resultsDir := t.TempDir()
+			mockBot := mockTelegramBot{
+				mSend: func(_ tgbotapi.Chattable) (tgbotapi.Message, error) {
+					return tgbotapi.Message{}, nil
+				},
+				receivedMessages: []string{},
+			}
+			p := New(&mockBot, 1,
+				WithOutputDirectory(&resultsDir),
+				withoutShuffle(),
+				WithAudienceJurors(tt.audience),
+				WithJudgeScoring(tt.scoring),
+			)
+
+			santana := &tgbotapi.User{ID: 666, UserName: "Santana"}
+			jesus := &tgbotapi.User{ID: 123, UserName: "Jesus"}
+			pjotr := &tgbotapi.User{ID: 7, UserName: "Pjotr"}
+			maria := &tgbotapi.User{ID: 42, UserName: "Maria"}
+			for _, update := range []tgbotapi.Message{
+				testMessage(santana, "levyraati aloita"),
+				testMessage(jesus, "levyraati liity"),
+				testMessage(pjotr, "levyraati liity"),
+				testMessage(santana, "levyraati jatka"),
+				testMessage(santana, "levyraati esitä Song1 https://example.com/1"),
+				testMessage(jesus, "levyraati esitä Song2 https://example.com/2"),
+				testMessage(pjotr, "levyraati esitä Song3 https://example.com/3"),
+				// Santana's song
+				testMessage(maria, "levyraati liity"),
+				testMessage(maria, "levyraati arvioi Meh 2/10"),
+				testMessage(jesus, "levyraati arvioi Good 8/10"),
+				testMessage(pjotr, "levyraati arvioi Fine 6/10"),
+				// Jesus' song
+				testMessage(maria, "levyraati arvioi Great 9/10"),
+				testMessage(santana, "levyraati arvioi Bland 4/10"),
+				testMessage(pjotr, "levyraati arvioi Best 10/10"),
+				// Pjotr's song
+				testMessage(maria, "levyraati arvioi Average 5/10"),
+				testMessage(santana, "levyraati arvioi Average 5/10"),
+				testMessage(jesus, "levyraati arvioi Average 5/10"),
+			} {
+				msg, err := ParseToMessage(tgbotapi.Update{Message: &update})
+				if err != nil {
+					t.Fatalf("Failed to parse %q: %#v", update.Text, err)
+				}
+				p.Handle(msg)
+			}
+			if p.State() != StateInit {
+				t.Fatalf("State = %s, want %s", p.State(), StateInit)
+			}
+
+			// The ranking follows the winner, before the game is cleared
+			sent := mockBot.receivedMessages
+			ranking := sent[len(sent)-2]
+			if tt.ranking == "" {
+				if strings.Contains(ranking, "Best judges") {
+					t.Errorf("Judges were ranked without judge scoring: %q", ranking)
+				}
+				return
+			}
+			if ranking != tt.ranking {
+				t.Errorf("Ranking = %q, want %q", ranking, tt.ranking)
+			}
+
+			results, err := filepath.Glob(filepath.Join(resultsDir, "jukebox_jury_results_*.html"))
+			if err != nil || len(results) != 1 {
+				t.Fatalf("Expected a single results file, got %v: %v", results, err)
+			}
+			html, err := os.ReadFile(results[0])
+			if err != nil {
+				t.Fatalf("Failed to read results: %v", err)
+			}
+			for _, expected := range tt.results {
+				if !strings.Contains(string(html), expected) {
+					t.Errorf("Results don't contain %q", expected)
+				}
+			}
+		})
+	}
+}
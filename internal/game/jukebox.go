@@ -1,24 +1,22 @@
 package game
 
-type StateFunc func(msg Message) StateFunc
-
 // Jukebox service interface
 type JukeboxServicer interface {
-	StartGame(msg Message) StateFunc
-	WaitPanelistsToJoin(msg Message) StateFunc
-	WaitForReviews(msg Message) StateFunc
-	AddSong(msg Message) StateFunc
-	IntroduceSong(msg Message) StateFunc
-	RevealReviews(msg Message) StateFunc
-	StopGame(_ Message) StateFunc
+	Handle(msg Message)
+	State() State
 }
 
 type Message struct {
-	Command    string
-	Text       string
-	PlayerName string
-	FromID     int64
-	ChatID     int64
+	Command    string `json:"command"`
+	Text       string `json:"text"`
+	PlayerName string `json:"player_name"`
+	// CallbackID identifies the button press the message came from
+	CallbackID string `json:"callback_id,omitempty"`
+	// Links are the URLs of the message, or of the message replied to
+	Links  []string `json:"links,omitempty"`
+	Voice  string   `json:"voice,omitempty"` // File ID of the voice note, or the own one replied to
+	FromID int64    `json:"from_id"`
+	ChatID int64    `json:"chat_id"`
 }
 
 func (m Message) IsEmpty() bool {
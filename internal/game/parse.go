@@ -0,0 +1,244 @@
+package game
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+var (
+	ErrOtherBot   = errors.New("command addressed to another bot")
+	ErrNoCommand  = fmt.Errorf("%w: command missing", ErrInvaliSyntax)
+	ErrNoText     = errors.New("message has no text")
+	ErrNoSender   = errors.New("message has no sender")
+	ErrChatOpened = errors.New("private chat with the bot opened")
+)
+
+// ParseError tells why a message couldn't be parsed. Errors with
+// ErrForUser are explained to the sender, the rest are messages which
+// weren't meant for the bot.
+type ParseError struct {
+	Err error
+	// ErrForUser is formatted with the prefix and the help command
+	ErrForUser messageID
+}
+
+func (e ParseError) Error() string {
+	return fmt.Sprintf("parse message: %s", e.Err)
+}
+
+func (e ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Parser turns Telegram messages into game messages. Besides the
+// "<prefix> <command> <text>" form, commands are accepted as Telegram
+// slash commands: /<command>, /<prefix>_<command> and /<prefix> <command>,
+// optionally addressed to the bot with @<bot name>. Slash commands which
+// aren't known commands or aliases are taken to be for other bots in the
+// chat, as is the /start which clients send when a private chat with the
+// bot is opened.
+//
+// Words can be separated by any whitespace, and the whitespace of the text
+// is collapsed into single spaces. Captions are parsed like texts, so that
+// a song can be shared with the command as its caption. Links of the message
+// and, lacking them, of the message replied to are collected to Links,
+// and a voice note of either to Voice.
+// A link sent in a private chat without the prefix is a shared song, and
+// presses of its confirmation buttons are parsed from the callback queries.
+type Parser struct {
+	commands map[string]string
+	prefix   string
+	botName  string
+}
+
+// NewParser creates a parser which knows the built-in commands. Use
+// Play.Parser to know the configured aliases too.
+func NewParser(prefix, botName string) Parser {
+	return Parser{prefix: prefix, botName: botName, commands: commandsFor(LanguageEnglish, nil)}
+}
+
+// Parser creates a parser for the commands of the game.
+func (p *Play) Parser(botName string) Parser {
+	return Parser{prefix: p.prefix, botName: botName, commands: p.commands}
+}
+
+// ParseToMessage parses the message with the default prefix.
+func ParseToMessage(u tgbotapi.Update) (Message, error) {
+	return NewParser(JukeboxJuryPrefix, "").Parse(u)
+}
+
+func (ps Parser) Parse(u tgbotapi.Update) (Message, error) {
+	if u.CallbackQuery != nil {
+		return parseCallback(u.CallbackQuery)
+	}
+	if u.Message == nil {
+		return Message{}, ParseError{Err: ErrNoText}
+	}
+	text, entities := messageContent(u.Message)
+	if strings.TrimSpace(text) == "" {
+		return Message{}, ParseError{Err: ErrNoText}
+	}
+	// Players open a private chat with the bot to submit their songs,
+	// which mustn't start a game
+	if word, _ := nextWord(text); u.Message.Chat.IsPrivate() && ps.isStart(word) {
+		return Message{}, ParseError{Err: ErrChatOpened}
+	}
+
+	command, rest, err := ps.tokenize(text)
+	// Songs are often shared from the apps as plain links
+	if errors.Is(err, ErrInvalidPrefix) && u.Message.Chat.IsPrivate() && len(links(text, entities)) > 0 {
+		command, rest, err = commandShareSong, text, nil
+	}
+	if err != nil {
+		return Message{}, err
+	}
+	// Anonymous group admins and channels post on behalf of a chat
+	if u.Message.From == nil || u.Message.SenderChat != nil {
+		return Message{}, ParseError{Err: ErrNoSender, ErrForUser: msgNoSender}
+	}
+
+	msg := Message{
+		Command: command,
+		Text:    strings.Join(strings.Fields(rest), " "),
+		Links:   links(text, entities),
+		FromID:  u.Message.From.ID,
+		ChatID:  u.Message.Chat.ID,
+	}
+	if u.Message.Voice != nil {
+		msg.Voice = u.Message.Voice.FileID
+	}
+	if reply := u.Message.ReplyToMessage; reply != nil {
+		replyText, replyEntities := messageContent(reply)
+		if msg.Text == "" {
+			msg.Text = strings.Join(strings.Fields(replyText), " ")
+		}
+		if len(msg.Links) == 0 {
+			msg.Links = links(replyText, replyEntities)
+		}
+		// Only the sender's own voice note counts as their review or commentary
+		ownReply := reply.From != nil && reply.From.ID == msg.FromID
+		if msg.Voice == "" && reply.Voice != nil && ownReply {
+			msg.Voice = reply.Voice.FileID
+		}
+	}
+
+	if u.Message.From.UserName == "" {
+		msg.PlayerName = u.Message.From.FirstName
+	} else {
+		msg.PlayerName = u.Message.From.UserName
+	}
+
+	return msg, nil
+}
+
+// tokenize returns the command and the rest of the text.
+func (ps Parser) tokenize(text string) (string, string, error) {
+	word, rest := nextWord(text)
+	if strings.HasPrefix(word, "/") {
+		return ps.parseSlashCommand(word, rest)
+	}
+
+	if !strings.EqualFold(word, ps.prefix) {
+		return "", "", ParseError{Err: ErrInvalidPrefix}
+	}
+	command, rest := nextWord(rest)
+	if command == "" {
+		return "", "", ParseError{Err: ErrNoCommand, ErrForUser: msgNoCommand}
+	}
+
+	return command, rest, nil
+}
+
+func (ps Parser) parseSlashCommand(word, rest string) (string, string, error) {
+	word, botName, addressed := strings.Cut(strings.TrimPrefix(word, "/"), "@")
+	if addressed && !strings.EqualFold(botName, ps.botName) {
+		return "", "", ParseError{Err: ErrOtherBot}
+	}
+
+	if command, found := cutPrefixFold(word, ps.prefix+"_"); found {
+		word = command
+	} else if strings.EqualFold(word, ps.prefix) {
+		word, rest = nextWord(rest)
+	}
+	if word == "" {
+		return "", "", ParseError{Err: ErrNoCommand, ErrForUser: msgNoCommand}
+	}
+	if _, known := ps.commands[strings.ToLower(word)]; !known {
+		return "", "", ParseError{Err: ErrOtherBot}
+	}
+
+	return word, rest, nil
+}
+
+// isStart tells whether the word is the /start command Telegram clients
+// send when a private chat with the bot is opened.
+func (ps Parser) isStart(word string) bool {
+	command, botName, addressed := strings.Cut(word, "@")
+	return strings.EqualFold(command, "/start") && (!addressed || strings.EqualFold(botName, ps.botName))
+}
+
+// cutPrefixFold is strings.CutPrefix which matches the prefix case
+// insensitively.
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) < len(prefix) || !strings.EqualFold(s[:len(prefix)], prefix) {
+		return s, false
+	}
+	return s[len(prefix):], true
+}
+
+// nextWord splits the first word of s from the rest.
+func nextWord(s string) (string, string) {
+	s = strings.TrimLeftFunc(s, unicode.IsSpace)
+	end := strings.IndexFunc(s, unicode.IsSpace)
+	if end == -1 {
+		return s, ""
+	}
+	return s[:end], s[end:]
+}
+
+// messageContent returns the text of the message, or the caption of
+// a media message, with its entities.
+func messageContent(m *tgbotapi.Message) (string, []tgbotapi.MessageEntity) {
+	if m.Text != "" {
+		return m.Text, m.Entities
+	}
+	return m.Caption, m.CaptionEntities
+}
+
+// links returns the URLs of the message, both the ones in the text and
+// the ones behind the text links.
+func links(text string, entities []tgbotapi.MessageEntity) []string {
+	var found []string
+	var units []uint16
+	for _, entity := range entities {
+		switch {
+		case entity.IsTextLink():
+			found = append(found, entity.URL)
+		case entity.IsURL():
+			// Entity offsets are counted in UTF-16 code units
+			if units == nil {
+				units = utf16.Encode([]rune(text))
+			}
+			if entity.Offset < 0 || entity.Offset+entity.Length > len(units) {
+				continue
+			}
+			found = append(found, string(utf16.Decode(units[entity.Offset:entity.Offset+entity.Length])))
+		}
+	}
+	return found
+}
+
+// ExplainParseError tells the sender why the message couldn't be parsed,
+// if the error is worth explaining.
+func (p *Play) ExplainParseError(chatID int64, err error) {
+	var parseErr ParseError
+	if !errors.As(err, &parseErr) || parseErr.ErrForUser == "" {
+		return
+	}
+	p.sendMessageToPanelist(chatID, p.tr(parseErr.ErrForUser, p.prefix, p.commandName(CommandHelp)))
+}
@@ -0,0 +1,231 @@
+package game
+
+import (
+	"errors"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParserSlashCommands(t *testing.T) {
+	parser := NewParser("jj", "JukeboxBot")
+	tests := []struct {
+		text        string
+		wantCommand string
+		wantText    string
+		wantErr     error
+	}{
+		{text: "jj liity", wantCommand: "liity"},
+		{text: "/join", wantCommand: "join"},
+		{text: "/join@jukeboxbot", wantCommand: "join"},
+		{text: "/jj_review Nice 7/10", wantCommand: "review", wantText: "Nice 7/10"},
+		{text: "/jj_review@JukeboxBot Nice 7/10", wantCommand: "review", wantText: "Nice 7/10"},
+		{text: "/JJ_review Nice 7/10", wantCommand: "review", wantText: "Nice 7/10"},
+		{text: "/jj esitä Song https://x.com", wantCommand: "esitä", wantText: "Song https://x.com"},
+		{text: "/join@OtherBot", wantErr: ErrOtherBot},
+		{text: "/weather Helsinki", wantErr: ErrOtherBot},
+		{text: "/weather@JukeboxBot", wantErr: ErrOtherBot},
+		{text: "/jj_weather", wantErr: ErrOtherBot},
+		{text: "/start", wantCommand: "start"},
+		{text: "/jj", wantErr: ErrInvaliSyntax},
+		{text: "levyraati liity", wantErr: ErrInvaliSyntax},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, err := parser.Parse(tgbotapi.Update{Message: &tgbotapi.Message{
+				Text: tt.text,
+				Chat: &tgbotapi.Chat{ID: 1},
+				From: &tgbotapi.User{ID: 2, UserName: "User1"},
+			}})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse(%q) error = %v, want %v", tt.text, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			want := Message{
+				ChatID:     1,
+				FromID:     2,
+				PlayerName: "User1",
+				Command:    tt.wantCommand,
+				Text:       tt.wantText,
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("Parse(%q) mismatch (-want +got):\n%s", tt.text, diff)
+			}
+		})
+	}
+}
+
+func TestParserMessageContent(t *testing.T) {
+	from := &tgbotapi.User{ID: 2, UserName: "User1"}
+	tests := []struct {
+		name    string
+		message tgbotapi.Message
+		want    Message
+	}{
+		{
+			name:    "Whitespace",
+			message: tgbotapi.Message{Text: "  levyraati\narvioi  Nice\n\nsong   7/10 ", From: from},
+			want:    Message{Command: "arvioi", Text: "Nice song 7/10"},
+		},
+		{
+			name:    "Capitalized prefix",
+			message: tgbotapi.Message{Text: "Levyraati liity", From: from},
+			want:    Message{Command: "liity"},
+		},
+		{
+			name: "Caption with a text link",
+			message: tgbotapi.Message{
+				Caption: "levyraati esitä Bangers only 🤘",
+				CaptionEntities: []tgbotapi.MessageEntity{
+					{Type: "text_link", Offset: 16, Length: 7, URL: "https://example.com/bangers"},
+				},
+				From: from,
+			},
+			want: Message{
+				Command: "esitä",
+				Text:    "Bangers only 🤘",
+				Links:   []string{"https://example.com/bangers"},
+			},
+		},
+		{
+			name: "URL after an emoji",
+			message: tgbotapi.Message{
+				Text: "levyraati esitä 🤘 https://example.com/x Great",
+				Entities: []tgbotapi.MessageEntity{
+					{Type: "url", Offset: 19, Length: 21},
+				},
+				From: from,
+			},
+			want: Message{
+				Command: "esitä",
+				Text:    "🤘 https://example.com/x Great",
+				Links:   []string{"https://example.com/x"},
+			},
+		},
+		{
+			name: "Reply to a song",
+			message: tgbotapi.Message{
+				Text: "levyraati esitä",
+				ReplyToMessage: &tgbotapi.Message{
+					Text:     "Listen to this https://example.com/reply",
+					Entities: []tgbotapi.MessageEntity{{Type: "url", Offset: 15, Length: 25}},
+				},
+				From: from,
+			},
+			want: Message{
+				Command: "esitä",
+				Text:    "Listen to this https://example.com/reply",
+				Links:   []string{"https://example.com/reply"},
+			},
+		},
+		{
+			name: "Reply to a voice note",
+			message: tgbotapi.Message{
+				Text: "levyraati kommentoi",
+				ReplyToMessage: &tgbotapi.Message{
+					Voice: &tgbotapi.Voice{FileID: "voice-1"},
+					From:  from,
+				},
+				From: from,
+			},
+			want: Message{Command: "kommentoi", Voice: "voice-1"},
+		},
+		{
+			name: "Reply to another user's voice note",
+			message: tgbotapi.Message{
+				Text: "levyraati kommentoi",
+				ReplyToMessage: &tgbotapi.Message{
+					Voice: &tgbotapi.Voice{FileID: "voice-1"},
+					From:  &tgbotapi.User{ID: 3, UserName: "User2"},
+				},
+				From: from,
+			},
+			want: Message{Command: "kommentoi"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.message.Chat = &tgbotapi.Chat{ID: 1}
+			got, err := ParseToMessage(tgbotapi.Update{Message: &tt.message})
+			if err != nil {
+				t.Fatalf("Parse() error: %v", err)
+			}
+			tt.want.ChatID = 1
+			tt.want.FromID = 2
+			tt.want.PlayerName = "User1"
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Parse() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParserErrors(t *testing.T) {
+	tests := []struct {
+		name          string
+		message       *tgbotapi.Message
+		want          error
+		wantExplained bool
+	}{
+		{name: "No message", want: ErrNoText},
+		{name: "Photo without caption", message: &tgbotapi.Message{}, want: ErrNoText},
+		{name: "Chatter", message: &tgbotapi.Message{Text: "hello"}, want: ErrInvalidPrefix},
+		{name: "Command of another bot", message: &tgbotapi.Message{Text: "/weather"}, want: ErrOtherBot},
+		{
+			name: "Private chat opened",
+			message: &tgbotapi.Message{
+				Text: "/start",
+				Chat: &tgbotapi.Chat{ID: 2, Type: "private"},
+				From: &tgbotapi.User{ID: 2, UserName: "User1"},
+			},
+			want: ErrChatOpened,
+		},
+		{
+			name:          "Prefix only",
+			message:       &tgbotapi.Message{Text: "levyraati \n"},
+			want:          ErrNoCommand,
+			wantExplained: true,
+		},
+		{
+			name:          "Channel post",
+			message:       &tgbotapi.Message{Text: "levyraati liity"},
+			want:          ErrNoSender,
+			wantExplained: true,
+		},
+		{
+			name: "Anonymous admin",
+			message: &tgbotapi.Message{
+				Text:       "levyraati liity",
+				From:       &tgbotapi.User{ID: 1087968824, UserName: "GroupAnonymousBot"},
+				SenderChat: &tgbotapi.Chat{ID: 1},
+			},
+			want:          ErrNoSender,
+			wantExplained: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.message != nil && tt.message.Chat == nil {
+				tt.message.Chat = &tgbotapi.Chat{ID: 1}
+			}
+			_, err := ParseToMessage(tgbotapi.Update{Message: tt.message})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.want)
+			}
+
+			mockBot := mockTelegramBot{
+				mSend: func(_ tgbotapi.Chattable) (tgbotapi.Message, error) {
+					return tgbotapi.Message{}, nil
+				},
+			}
+			New(&mockBot, 1).ExplainParseError(1, err)
+			if explained := len(mockBot.receivedMessages) > 0; explained != tt.wantExplained {
+				t.Errorf("ExplainParseError() sent %v, want a reply %v",
+					mockBot.receivedMessages, tt.wantExplained)
+			}
+		})
+	}
+}
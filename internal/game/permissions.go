@@ -0,0 +1,177 @@
+package game
+
+import (
+	"fmt"
+	"slices"
+
+	"weezel/jukeboxjury/internal/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Role is a bit set of the privileges a user has in the game.
+type Role uint8
+
+const (
+	RoleGameStarter Role = 1 << iota
+	RoleBotAdmin
+	RoleChatAdmin
+)
+
+var roleNames = []struct {
+	text messageID
+	role Role
+}{
+	{role: RoleGameStarter, text: msgRoleGameStarter},
+	{role: RoleBotAdmin, text: msgRoleBotAdmins},
+	{role: RoleChatAdmin, text: msgRoleChatAdmins},
+}
+
+// String lists the roles in English, e.g. for the logs.
+func (r Role) String() string {
+	english := messages[LanguageEnglish]
+	names := []string{}
+	for _, rn := range roleNames {
+		if r&rn.role != 0 {
+			names = append(names, english[rn.text])
+		}
+	}
+	return joinAlternatives(names, english[msgRoleNobody], english[msgOr])
+}
+
+// describeRoles lists the roles in the language of the game.
+func (p *Play) describeRoles(r Role) richText {
+	names := []richText{}
+	for _, rn := range roleNames {
+		if r&rn.role != 0 {
+			names = append(names, p.tr(rn.text))
+		}
+	}
+	return joinAlternatives(names, p.tr(msgRoleNobody), p.tr(msgOr))
+}
+
+// joinAlternatives joins the names into "a, b or c".
+func joinAlternatives[T ~string](names []T, none T, or T) T {
+	switch len(names) {
+	case 0:
+		return none
+	case 1:
+		return names[0]
+	}
+	joined := names[0]
+	for _, name := range names[1 : len(names)-1] {
+		joined += ", " + name
+	}
+	return joined + " " + or + " " + names[len(names)-1]
+}
+
+// Action is a game control command which requires a permission.
+type Action int
+
+const (
+	ActionContinue Action = iota
+	ActionStop
+	ActionKick
+	ActionSkip
+)
+
+var actionTexts = map[Action]messageID{
+	ActionContinue: msgActionContinue,
+	ActionStop:     msgActionStop,
+	ActionKick:     msgActionKick,
+	ActionSkip:     msgActionSkip,
+}
+
+// String describes the action in English, e.g. for the logs.
+func (a Action) String() string {
+	if text, ok := actionTexts[a]; ok {
+		return messages[LanguageEnglish][text]
+	}
+	return fmt.Sprintf("action(%d)", int(a))
+}
+
+// describeAction describes the action in the language of the game.
+func (p *Play) describeAction(a Action) richText {
+	if text, ok := actionTexts[a]; ok {
+		return p.tr(text)
+	}
+	return escape(a.String())
+}
+
+// defaultPermissions lists the roles allowed to perform each action
+// unless overridden with WithPermission.
+func defaultPermissions() map[Action]Role {
+	all := RoleGameStarter | RoleBotAdmin | RoleChatAdmin
+	return map[Action]Role{
+		ActionContinue: all,
+		ActionStop:     all,
+		ActionKick:     all,
+		ActionSkip:     all,
+	}
+}
+
+// WithBotAdmins sets the Telegram user IDs which are always allowed
+// to control the game.
+func WithBotAdmins(uids ...int64) PlayOption {
+	return func(p *Play) {
+		p.botAdmins = slices.Clone(uids)
+	}
+}
+
+// WithPermission overrides the roles allowed to perform the action.
+func WithPermission(action Action, roles Role) PlayOption {
+	return func(p *Play) {
+		p.permissions[action] = roles
+	}
+}
+
+// rolesOf resolves the roles of the user. Chat admins are only queried
+// from Telegram when needed is not already satisfied by the other roles.
+func (p *Play) rolesOf(uid int64, needed Role) Role {
+	var roles Role
+	if p.gameStarterUID != 0 && p.gameStarterUID == uid {
+		roles |= RoleGameStarter
+	}
+	if slices.Contains(p.botAdmins, uid) {
+		roles |= RoleBotAdmin
+	}
+	if roles&needed != 0 || needed&RoleChatAdmin == 0 {
+		return roles
+	}
+
+	admins, err := p.bot.GetChatAdministrators(tgbotapi.ChatAdministratorsConfig{
+		ChatConfig: tgbotapi.ChatConfig{ChatID: p.chatID},
+	})
+	if err != nil {
+		logger.Logger.Error().Err(err).Int64("chat_id", p.chatID).Msg("Failed to fetch chat administrators")
+		return roles
+	}
+	adminUIDs := make([]int64, 0, len(admins))
+	for _, admin := range admins {
+		if admin.User != nil {
+			adminUIDs = append(adminUIDs, admin.User.ID)
+		}
+	}
+	p.recordEvent(Event{Type: EventChatAdmins, ChatAdmins: adminUIDs})
+	if slices.Contains(adminUIDs, uid) {
+		roles |= RoleChatAdmin
+	}
+
+	return roles
+}
+
+// Authorize tells whether the sender of msg is allowed to perform the
+// action. Denied users are told who could do it instead.
+func (p *Play) Authorize(msg Message, action Action) bool {
+	allowed := p.permissions[action]
+	if p.rolesOf(msg.FromID, allowed)&allowed != 0 {
+		return true
+	}
+
+	logger.Logger.Warn().
+		Str("action", action.String()).
+		Msgf("Panelist %s with ID %d is not allowed to perform the action", msg.PlayerName, msg.FromID)
+	p.sendMessageToPanelist(msg.ChatID, p.tr(msgNotAllowed, p.describeRoles(allowed), p.describeAction(action)))
+
+	return false
+}
@@ -0,0 +1,97 @@
+package game
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestAuthorize(t *testing.T) {
+	mockBot := mockTelegramBot{
+		mSend: func(_ tgbotapi.Chattable) (tgbotapi.Message, error) {
+			return tgbotapi.Message{}, nil
+		},
+		chatAdmins: []tgbotapi.ChatMember{
+			{User: &tgbotapi.User{ID: 3}, Status: "administrator"},
+		},
+		receivedMessages: []string{},
+	}
+
+	p := New(&mockBot, 1,
+		WithOutputDirectory(nil),
+		WithBotAdmins(2),
+		WithPermission(ActionKick, RoleBotAdmin),
+	)
+	p.gameStarterUID = 1
+
+	tests := []struct {
+		name   string
+		uid    int64
+		action Action
+		want   bool
+	}{
+		{name: "Game starter continues", uid: 1, action: ActionContinue, want: true},
+		{name: "Bot admin stops", uid: 2, action: ActionStop, want: true},
+		{name: "Chat admin skips", uid: 3, action: ActionSkip, want: true},
+		{name: "Panelist stops", uid: 4, action: ActionStop, want: false},
+		{name: "Game starter kicks", uid: 1, action: ActionKick, want: false},
+		{name: "Bot admin kicks", uid: 2, action: ActionKick, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Authorize(Message{FromID: tt.uid}, tt.action); got != tt.want {
+				t.Errorf("Authorize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	expected := "Sorry, only bot admins can kick panelists"
+	if last := mockBot.receivedMessages[len(mockBot.receivedMessages)-1]; last != expected {
+		t.Errorf("Denial message = %q, want %q", last, expected)
+	}
+}
+
+func TestAuthorizeFinnish(t *testing.T) {
+	mockBot := mockTelegramBot{
+		mSend: func(_ tgbotapi.Chattable) (tgbotapi.Message, error) {
+			return tgbotapi.Message{}, nil
+		},
+		receivedMessages: []string{},
+	}
+	p := New(&mockBot, 1,
+		WithOutputDirectory(nil),
+		WithLanguage(LanguageFinnish),
+		WithPermission(ActionSkip, RoleGameStarter|RoleBotAdmin),
+	)
+	p.gameStarterUID = 1
+
+	if p.Authorize(Message{FromID: 4}, ActionSkip) {
+		t.Fatal("Authorize() = true, want false")
+	}
+	expected := "Valitettavasti vain pelin aloittaja tai botin ylläpitäjät voi ohittaa kappaleita"
+	if last := mockBot.receivedMessages[len(mockBot.receivedMessages)-1]; last != expected {
+		t.Errorf("Denial message = %q, want %q", last, expected)
+	}
+}
+
+func TestRoleString(t *testing.T) {
+	tests := []struct {
+		want string
+		role Role
+	}{
+		{role: 0, want: "nobody"},
+		{role: RoleChatAdmin, want: "chat admins"},
+		{role: RoleGameStarter | RoleBotAdmin, want: "the game starter or bot admins"},
+		{
+			role: RoleGameStarter | RoleBotAdmin | RoleChatAdmin,
+			want: "the game starter, bot admins or chat admins",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.role.String(); got != tt.want {
+				t.Errorf("Role.String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
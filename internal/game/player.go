@@ -5,14 +5,24 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Song struct {
-	Description  string  `json:"description"`
-	URL          string  `json:"url"`
-	AverageScore float64 `json:"average_score"`
+	IntroducedAt  time.Time `json:"introduced_at"`
+	RevealedAt    time.Time `json:"revealed_at"`
+	Description   string    `json:"description"`
+	URL           string    `json:"url"`
+	Commentary    string    `json:"commentary,omitempty"` // Presenter's note on why they chose the song
+	VoiceNote     string    `json:"voice_note,omitempty"` // File ID of the presenter's voice commentary
+	AverageScore  float64   `json:"average_score"`
+	AudienceScore float64   `json:"audience_score"`
+	Prediction    float64   `json:"prediction,omitempty"` // Presenter's guess of the average score
+	AudienceVotes int       `json:"audience_votes"`
+	Predicted     bool      `json:"predicted,omitempty"`
 }
 
 func (s Song) String() string {
@@ -20,19 +30,27 @@ func (s Song) String() string {
 }
 
 type Review struct {
-	From   string `json:"from"`
-	Review string `json:"review"`
-	Rating int    `json:"rating"`
+	From     string `json:"from"`
+	Review   string `json:"review"`
+	Audio    string `json:"audio,omitempty"` // File name of the voice review next to the results
+	Rating   int    `json:"rating"`
+	uid      int64  // Reviewer, for scoring the judges
+	Audience bool   `json:"audience"`
 }
 
 type Panelist struct {
 	Name            string    `json:"name"`
+	Team            string    `json:"team,omitempty"`
 	Song            *Song     `json:"song"`
 	ReceivedReviews []*Review `json:"received_reviews"`
+	JudgeScore      float64   `json:"judge_score,omitempty"`
+	EliminatedIn    int       `json:"eliminated_in,omitempty"` // Round of an elimination game
 	ReviewGiven     bool
 	SongSubmitted   bool
 	SongPresented   bool
 	uid             int64
+	audience        bool
+	judged          bool // Has reviewed a revealed song
 }
 
 func NewPanelist(name string, uid int64) *Panelist {
@@ -47,61 +65,116 @@ func NewPanelist(name string, uid int64) *Panelist {
 var (
 	ErrNoReview     = errors.New("no review")
 	ErrParseSongURL = errors.New("song URL ist kaput")
+	ErrPrediction   = errors.New("predicted score out of range")
 )
 
-func (p *Panelist) AddSong(msg Message) error {
+// AddSong adds the panelist's song. With a positive maxPrediction the song
+// may end with the panelist's prediction of its average score, e.g. 7.5/10.
+func (p *Panelist) AddSong(msg Message, maxPrediction int) error {
+	prediction, predicted, err := cutPrediction(&msg, maxPrediction)
+	if err != nil {
+		return err
+	}
+	song, err := parseSong(msg)
+	if err != nil {
+		return err
+	}
+	song.Prediction, song.Predicted = prediction, predicted
+
+	p.Song = song
+	p.SongSubmitted = true
+
+	return nil
+}
+
+var predictionPat = regexp.MustCompile(`^([0-9]+(?:[.,][0-9]+)?)/[0-9]+$`)
+
+// cutPrediction removes the predicted score from the end of the message
+// and returns it. Returns false when the message has no prediction.
+func cutPrediction(msg *Message, maxPrediction int) (float64, bool, error) {
+	fields := strings.Fields(msg.Text)
+	if maxPrediction <= 0 || len(fields) == 0 {
+		return 0, false, nil
+	}
+	match := predictionPat.FindStringSubmatch(fields[len(fields)-1])
+	if match == nil {
+		return 0, false, nil
+	}
+	prediction, err := strconv.ParseFloat(strings.Replace(match[1], ",", ".", 1), 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("parse prediction %q: %w", match[0], err)
+	}
+	if prediction > float64(maxPrediction) {
+		return 0, false, fmt.Errorf("%w: %s", ErrPrediction, match[0])
+	}
+
+	msg.Text = strings.Join(fields[:len(fields)-1], " ")
+	return prediction, true, nil
+}
+
+// parseSong parses the description and the URL of the song from the message.
+func parseSong(msg Message) (*Song, error) {
 	if len(msg.Text) < 1 {
-		return ErrNoReview
+		return nil, ErrNoReview
 	}
 
+	// Prefer the links Telegram recognized, the URL might be behind
+	// a text link or in the middle of the description
 	splt := strings.Fields(msg.Text)
 	possibleURL := splt[len(splt)-1]
+	if len(msg.Links) > 0 {
+		possibleURL = msg.Links[len(msg.Links)-1]
+	}
 	songURL, err := url.Parse(possibleURL)
 	if err != nil {
-		return fmt.Errorf("song url %q: %w", possibleURL, ErrParseSongURL)
+		return nil, fmt.Errorf("song url %q: %w", possibleURL, ErrParseSongURL)
 	}
 
-	description := strings.Join(splt[0:len(splt)-1], " ")
+	description := strings.Join(slices.DeleteFunc(splt, func(word string) bool {
+		return word == possibleURL
+	}), " ")
 
-	p.Song = &Song{
+	return &Song{
 		Description: description,
 		URL:         songURL.String(),
-	}
-	p.SongSubmitted = true
-
-	return nil
+	}, nil
 }
 
 type ReviewError struct {
-	Err        error
-	ErrForUser string
+	Err error
+	// ErrForUser is formatted with the highest rating
+	ErrForUser messageID
 }
 
 func (r ReviewError) Error() string {
 	return fmt.Sprintf("add review: %s", r.Err)
 }
 
-func (p *Panelist) AddReview(reviewer *Panelist, review string) error {
-	rating, err := parseRating(review)
+// AddReview adds the reviewer's review of the panelist's song. The rating
+// must be between zero and maxRating.
+func (p *Panelist) AddReview(reviewer *Panelist, review string, maxRating int) error {
+	rating, err := parseRating(review, maxRating)
 	if err != nil {
 		return ReviewError{
-			Err: fmt.Errorf("parse rating from user=%s, review=%s, error: %w", reviewer.Name, review, err),
-			ErrForUser: "Did you forgot to give the points? " +
-				"Those should be in 0/10 format and as a last item.",
+			Err: fmt.Errorf("parse rating from user=%s, review=%s, error: %w",
+				reviewer.Name, review, err),
+			ErrForUser: msgRatingMissing,
 		}
 	}
 	cleanedReview := strings.LastIndex(review, " ")
 	if cleanedReview == -1 {
 		return ReviewError{
 			Err:        fmt.Errorf("parse last space char from user %s, review %q", reviewer.Name, review),
-			ErrForUser: "Check that the scoring is last item and separated with a space: ... 0/10",
+			ErrForUser: msgRatingNotLast,
 		}
 	}
 
 	p.ReceivedReviews = append(p.ReceivedReviews, &Review{
-		Rating: rating,
-		From:   reviewer.Name,
-		Review: review[0:cleanedReview],
+		Rating:   rating,
+		From:     reviewer.Name,
+		uid:      reviewer.uid,
+		Review:   strings.TrimSpace(review[0:cleanedReview]),
+		Audience: reviewer.audience,
 	})
 
 	reviewer.ReviewGiven = true
@@ -112,7 +185,7 @@ func (p *Panelist) AddReview(reviewer *Panelist, review string) error {
 var ratingPat = regexp.MustCompile("[0-9]+/[0-9]+$")
 
 // TODO simplify
-func parseRating(review string) (int, error) {
+func parseRating(review string, maxRating int) (int, error) {
 	match := ratingPat.FindString(review)
 	if match == "" {
 		return -1, errors.New("couldn't parse review points")
@@ -123,7 +196,7 @@ func parseRating(review string) (int, error) {
 		return -1, fmt.Errorf("failed to parse rating: %w", err)
 	}
 
-	if numPoints < 0 || numPoints > 10 {
+	if numPoints < 0 || numPoints > maxRating {
 		return -1, fmt.Errorf("number was out of range: %d", numPoints)
 	}
 
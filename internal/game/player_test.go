@@ -0,0 +1,128 @@
+package game
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseRatingScale(t *testing.T) {
+	tests := []struct {
+		review    string
+		maxRating int
+		want      int
+		wantErr   bool
+	}{
+		{review: "Nice 7/10", maxRating: 10, want: 7},
+		{review: "Nice 0/10", maxRating: 10, want: 0},
+		{review: "Nice 11/10", maxRating: 10, wantErr: true},
+		{review: "Nice 5/5", maxRating: 5, want: 5},
+		{review: "Nice 7/5", maxRating: 5, wantErr: true},
+		{review: "No rating", maxRating: 5, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.review, func(t *testing.T) {
+			got, err := parseRating(tt.review, tt.maxRating)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRating(%q, %d) error = %v, wantErr %v",
+					tt.review, tt.maxRating, err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("parseRating(%q, %d) = %d, want %d", tt.review, tt.maxRating, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddSongLinks(t *testing.T) {
+	tests := []struct {
+		msg             Message
+		wantDescription string
+		wantURL         string
+	}{
+		{
+			msg:             Message{Text: "Great song https://example.com/last"},
+			wantDescription: "Great song",
+			wantURL:         "https://example.com/last",
+		},
+		{
+			msg: Message{
+				Text:  "Great https://example.com/middle song",
+				Links: []string{"https://example.com/middle"},
+			},
+			wantDescription: "Great song",
+			wantURL:         "https://example.com/middle",
+		},
+		{
+			msg:             Message{Text: "Great song", Links: []string{"https://example.com/text-link"}},
+			wantDescription: "Great song",
+			wantURL:         "https://example.com/text-link",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.msg.Text, func(t *testing.T) {
+			panelist := NewPanelist("Santana", 666)
+			if err := panelist.AddSong(tt.msg, 0); err != nil {
+				t.Fatalf("AddSong() error: %v", err)
+			}
+			if panelist.Song.Description != tt.wantDescription || panelist.Song.URL != tt.wantURL {
+				t.Errorf("AddSong() = %q %q, want %q %q",
+					panelist.Song.Description, panelist.Song.URL, tt.wantDescription, tt.wantURL)
+			}
+		})
+	}
+}
+
+func TestAddSongPrediction(t *testing.T) {
+	tests := []struct {
+		text            string
+		maxPrediction   int
+		wantDescription string
+		wantPrediction  float64
+		wantPredicted   bool
+		wantErr         error
+	}{
+		{
+			text:            "Great song https://example.com/1 7.5/10",
+			maxPrediction:   10,
+			wantDescription: "Great song",
+			wantPrediction:  7.5,
+			wantPredicted:   true,
+		},
+		{
+			text:            "Great song https://example.com/1 6,25/10",
+			maxPrediction:   10,
+			wantDescription: "Great song",
+			wantPrediction:  6.25,
+			wantPredicted:   true,
+		},
+		{
+			text:            "Great song https://example.com/1",
+			maxPrediction:   10,
+			wantDescription: "Great song",
+		},
+		{
+			text:          "Great song https://example.com/1 11/10",
+			maxPrediction: 10,
+			wantErr:       ErrPrediction,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			panelist := NewPanelist("Santana", 666)
+			err := panelist.AddSong(Message{Text: tt.text}, tt.maxPrediction)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AddSong() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			song := panelist.Song
+			if song.Description != tt.wantDescription || song.URL != "https://example.com/1" ||
+				song.Prediction != tt.wantPrediction || song.Predicted != tt.wantPredicted {
+				t.Errorf("AddSong() = %q %q %v %v, want %q %v %v",
+					song.Description, song.URL, song.Prediction, song.Predicted,
+					tt.wantDescription, tt.wantPrediction, tt.wantPredicted)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package game
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// WithScorePredictions lets the panelists predict the average score of
+// their own song when adding it. The predictions are revealed with the
+// reviews and ranked by their accuracy at the end of the game.
+func WithScorePredictions(enabled bool) PlayOption {
+	return func(p *Play) {
+		p.scorePredictions = enabled
+	}
+}
+
+// predictionError tells how far the prediction was from the song's score.
+func predictionError(song *Song) float64 {
+	return math.Abs(song.AverageScore - song.Prediction)
+}
+
+// BestPredictions ranks the panelists by how close they predicted the
+// score of their song, the closest first. Panelists who didn't predict,
+// or whose song wasn't revealed, are left out.
+func (p Play) BestPredictions() []*Panelist {
+	predictors := []*Panelist{}
+	for _, panelist := range p.Panelists {
+		if panelist.Song.Predicted && !panelist.Song.RevealedAt.IsZero() {
+			predictors = append(predictors, panelist)
+		}
+	}
+	slices.SortStableFunc(predictors, func(a, b *Panelist) int {
+		return cmp.Compare(predictionError(a.Song), predictionError(b.Song))
+	})
+	return predictors
+}
+
+// announceBestPredictions tells the channel who knew their song best.
+func (p *Play) announceBestPredictions() {
+	predictors := p.BestPredictions()
+	if len(predictors) == 0 {
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(string(p.tr(msgBestPredictions)))
+	for i, predictor := range predictors {
+		song := predictor.Song
+		fmt.Fprintf(&sb, "\n%d. %s", i+1,
+			p.tr(msgPredictionRank, bold(predictor.Name), song.Prediction, song.AverageScore))
+	}
+	p.sendMessageToChannel(richText(sb.String()))
+}
@@ -0,0 +1,84 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestScorePredictions(t *testing.T) {
+	resultsDir := t.TempDir()
+	p, bot := newScriptedGame(t, WithOutputDirectory(&resultsDir), WithScorePredictions(true))
+
+	santana := &tgbotapi.User{ID: 666, UserName: "Santana"}
+	jesus := &tgbotapi.User{ID: 123, UserName: "Jesus"}
+	pjotr := &tgbotapi.User{ID: 7, UserName: "Pjotr"}
+	runScript(t, p, bot, []scriptStep{
+		{update: testMessage(santana, "levyraati aloita"), want: StateWaitPanelistsToJoin},
+		{update: testMessage(jesus, "levyraati liity"), want: StateWaitPanelistsToJoin},
+		{update: testMessage(pjotr, "levyraati liity"), want: StateWaitPanelistsToJoin},
+		{update: testMessage(santana, "levyraati jatka"), want: StateAddSong},
+		{
+			update:   testMessage(santana, "levyraati esitä Song1 https://example.com/1 11/10"),
+			want:     StateAddSong,
+			response: "The predicted score is higher than the highest rating",
+		},
+		{update: testMessage(santana, "levyraati esitä Song1 https://example.com/1 7/10"), want: StateAddSong},
+		{update: testMessage(jesus, "levyraati esitä Song2 https://example.com/2 3/10"), want: StateAddSong},
+		{update: testMessage(pjotr, "levyraati esitä Song3 https://example.com/3"), want: StateWaitForReviews},
+		{update: testMessage(jesus, "levyraati arvioi Good 8/10"), want: StateWaitForReviews},
+		{update: testMessage(pjotr, "levyraati arvioi Fine 6/10"), want: StateWaitForReviews},
+		{update: testMessage(santana, "levyraati arvioi Bland 4/10"), want: StateWaitForReviews},
+		{update: testMessage(pjotr, "levyraati arvioi Best 10/10"), want: StateWaitForReviews},
+		{update: testMessage(santana, "levyraati arvioi Average 5/10"), want: StateWaitForReviews},
+		{update: testMessage(jesus, "levyraati arvioi Average 5/10"), want: StateInit},
+	})
+
+	// Predictions are kept secret until the reveal
+	sent := bot.receivedMessages
+	introduced := slices.IndexFunc(sent, func(m string) bool { return strings.Contains(m, "Song1") })
+	revealed := slices.IndexFunc(sent, func(m string) bool {
+		return strings.Contains(m, "<b>Santana</b> predicted")
+	})
+	if introduced == -1 || revealed < introduced || strings.Contains(sent[introduced], "7/10") {
+		t.Errorf("Prediction wasn't kept secret:\n%s", strings.Join(sent, "\n"))
+	}
+
+	all := strings.Join(sent, "\n")
+	for _, want := range []string{
+		"Predict the average score of your song by ending the command with it, " +
+			"e.g. levyraati esitä description https://link 7.5/10. " +
+			"The prediction is revealed with the reviews",
+		"Eventually the song <a href=\"https://example.com/1\">Song1</a> ended up catching " +
+			"7.00 points, <b>Santana</b> predicted 7.00",
+		"Closest predictions of the own song's score:\n" +
+			"1. <b>Santana</b> predicted 7.00 and got 7.00\n" +
+			"2. <b>Jesus</b> predicted 3.00 and got 7.00",
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("Message %q wasn't sent:\n%s", want, all)
+		}
+	}
+
+	results, err := filepath.Glob(filepath.Join(resultsDir, "jukebox_jury_results_*.html"))
+	if err != nil || len(results) != 1 {
+		t.Fatalf("Expected a single results file, got %v: %v", results, err)
+	}
+	html, err := os.ReadFile(results[0])
+	if err != nil {
+		t.Fatalf("Failed to read results: %v", err)
+	}
+	for _, expected := range []string{
+		"<strong>Predicted Score:</strong> 3",
+		"<li>Santana: predicted 7.00, got 7.00</li>",
+		"<li>Jesus: predicted 3.00, got 7.00</li>",
+	} {
+		if !strings.Contains(string(html), expected) {
+			t.Errorf("Results don't contain %q", expected)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package game
+
+import (
+	"html"
+	"regexp"
+	"strings"
+
+	"weezel/jukeboxjury/internal/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// richText is a text formatted with Telegram HTML. Plain strings become
+// rich text by escaping them, so that user-provided names, descriptions and
+// reviews can't break the formatting.
+type richText string
+
+var (
+	htmlEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+	htmlTag     = regexp.MustCompile(`<[^>]*>`)
+)
+
+// escape turns a plain text into rich text. Telegram requires only these
+// three characters to be escaped.
+func escape(s string) richText {
+	return richText(htmlEscaper.Replace(s))
+}
+
+func bold(s string) richText {
+	return "<b>" + escape(s) + "</b>"
+}
+
+func code(s string) richText {
+	return "<code>" + escape(s) + "</code>"
+}
+
+// songLink renders the song as its description linking to the song. Songs
+// without a web link show the description and the URL as they were given.
+func songLink(song *Song) richText {
+	title := song.Description
+	if title == "" {
+		title = song.URL
+	}
+	u, ok := webURL(song.URL)
+	if !ok {
+		if title == song.URL {
+			return escape(title)
+		}
+		return escape(title + " " + song.URL)
+	}
+	return richText(`<a href="`+html.EscapeString(u.String())+`">`) + escape(title) + "</a>"
+}
+
+// plain strips the formatting for places which don't support it, such as
+// poll questions, buttons and callback answers.
+func (t richText) plain() string {
+	return html.UnescapeString(htmlTag.ReplaceAllString(string(t), ""))
+}
+
+// newMessage creates a HTML formatted message. Link previews are shown
+// only for messages about a song, the rest would be cluttered by previews
+// of result pages.
+func newMessage(chatID int64, text richText, preview bool) tgbotapi.MessageConfig {
+	msg := tgbotapi.NewMessage(chatID, string(text))
+	msg.ParseMode = tgbotapi.ModeHTML
+	msg.DisableWebPagePreview = !preview
+	return msg
+}
+
+// sendMessageToChannel sends text parameter to Telegram channel and logs failed sends.
+func (p *Play) sendMessageToChannel(text richText) {
+	p.send(newMessage(p.chatID, text, false), "Error sending channel message")
+}
+
+// sendMessageToPanelist sends text parameter to panelist and logs failed sends.
+func (p *Play) sendMessageToPanelist(chatID int64, text richText) {
+	p.send(newMessage(chatID, text, false), "Error sending user message")
+}
+
+func (p *Play) send(msg tgbotapi.MessageConfig, errMsg string) {
+	if _, err := p.bot.Send(msg); err != nil {
+		logger.Logger.Error().Err(err).Str("payload", msg.Text).Msg(errMsg)
+	}
+}
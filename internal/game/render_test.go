@@ -0,0 +1,102 @@
+package game
+
+import (
+	"slices"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestSongLink(t *testing.T) {
+	tests := []struct {
+		name     string
+		song     Song
+		expected richText
+	}{
+		{
+			name:     "Description links to the song",
+			song:     Song{Description: "Rock & <roll>", URL: "https://example.com/?a=1&b=\"2\""},
+			expected: `<a href="https://example.com/?a=1&amp;b=&#34;2&#34;">Rock &amp; &lt;roll&gt;</a>`,
+		},
+		{
+			name:     "URL is the title without description",
+			song:     Song{URL: "https://example.com/song"},
+			expected: `<a href="https://example.com/song">https://example.com/song</a>`,
+		},
+		{
+			name:     "Not a web link",
+			song:     Song{Description: "Mixtape", URL: "tape<1>"},
+			expected: "Mixtape tape&lt;1&gt;",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := songLink(&tt.song); got != tt.expected {
+				t.Errorf("songLink() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUserTextIsEscaped(t *testing.T) {
+
+	var sent []tgbotapi.MessageConfig
+	mockBot := mockTelegramBot{
+		mSend: func(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+			if msg, ok := c.(tgbotapi.MessageConfig); ok {
+				sent = append(sent, msg)
+			}
+			return tgbotapi.Message{}, nil
+		},
+		receivedMessages: []string{},
+	}
+
+	tom := &tgbotapi.User{ID: 1, FirstName: "Tom & <b>Jerry"}
+	jesus := &tgbotapi.User{ID: 2, UserName: "Jesus"}
+	updates := []tgbotapi.Message{
+		testMessage(tom, "levyraati aloita"),
+		testMessage(jesus, "levyraati liity"),
+		testMessage(tom, "levyraati jatka"),
+		testMessage(tom, "levyraati esitä <i>Cats</i> https://example.com/1"),
+		testMessage(jesus, "levyraati esitä Hallelujah https://example.com/2"),
+		testMessage(jesus, "levyraati arvioi 1 < 2 && </b> 8/10"),
+	}
+	p := New(&mockBot, 1, WithOutputDirectory(nil), withoutShuffle())
+	for i, update := range updates {
+		msg, err := ParseToMessage(tgbotapi.Update{Message: &update})
+		if err != nil {
+			t.Fatalf("Failed to parse %d %q: %#v", i, update.Text, err)
+		}
+		p.Handle(msg)
+	}
+
+	for _, expected := range []string{
+		"User <b>Tom &amp; &lt;b&gt;Jerry</b> started a new game, join by using command: levyraati liity",
+		"The next song comes from the panelist <b>Tom &amp; &lt;b&gt;Jerry</b>: " +
+			`<a href="https://example.com/1">&lt;i&gt;Cats&lt;/i&gt;</a>`,
+		"<b>Jesus</b> wrote: 1 &lt; 2 &amp;&amp; &lt;/b&gt;. The song rating was: 8/10\n\n" +
+			`Eventually the song <a href="https://example.com/1">&lt;i&gt;Cats&lt;/i&gt;</a> ` +
+			"ended up catching 8.00 points",
+	} {
+		if !slices.Contains(mockBot.receivedMessages, expected) {
+			t.Errorf("Message %q not sent, got %q", expected, mockBot.receivedMessages)
+		}
+	}
+
+	// Only the song introductions have link previews
+	for _, msg := range sent {
+		if msg.ParseMode != tgbotapi.ModeHTML {
+			t.Errorf("Message %q parse mode = %q, want HTML", msg.Text, msg.ParseMode)
+		}
+		if !msg.DisableWebPagePreview {
+			t.Errorf("Message %q has a link preview", msg.Text)
+		}
+	}
+}
+
+func TestPlain(t *testing.T) {
+	text := richText(`<b>Tom &amp; Jerry</b> likes <a href="https://example.com">1 &lt; 2</a>`)
+	if got, expected := text.plain(), "Tom & Jerry likes 1 < 2"; got != expected {
+		t.Errorf("plain() = %q, want %q", got, expected)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"html/template"
 	"io"
 	"time"
+	_ "time/tzdata" // Fallback for systems without the time zone database
 
 	"weezel/jukeboxjury/internal/logger"
 )
@@ -13,27 +14,67 @@ import (
 //go:embed assets/results_template.html
 var resultsTemplate string
 
+// DefaultTimeFormat is the layout of the timestamps in the results
+// unless overridden with WithTimeFormat.
+const DefaultTimeFormat = "2006-01-02 15:04"
+
+// timeZone is the default time zone of the timestamps. The time zone
+// database is embedded in the binary for systems which lack one.
 var timeZone = func() *time.Location {
 	loc, err := time.LoadLocation("Europe/Helsinki")
 	if err != nil {
-		logger.Logger.Fatal().Err(err).Msg("Time zone not found")
+		logger.Logger.Error().Err(err).Msg("Time zone not found, using UTC")
+		return time.UTC
 	}
 	return loc
 }()
 
-var funcMap = template.FuncMap{
-	"timeNow": func() string {
-		return time.Now().In(timeZone).Format("2006-01-02T15:04:05")
-	},
-	"gameDuration": func(started time.Time) string {
-		return time.Since(started).String()
-	},
+func funcMap(loc *time.Location, layout string) template.FuncMap {
+	return template.FuncMap{
+		"formatTime": func(t time.Time) string {
+			return t.In(loc).Format(layout)
+		},
+		"humanDuration": func(from time.Time, to time.Time) string {
+			return humanDuration(to.Sub(from))
+		},
+	}
 }
 
-var tmpl = template.Must(template.New("results").Funcs(funcMap).Parse(resultsTemplate))
+var tmpl = template.Must(
+	template.New("results").Funcs(funcMap(timeZone, DefaultTimeFormat)).Parse(resultsTemplate),
+)
+
+// humanDuration formats the duration in hours and minutes, e.g. "1 h 23 min".
+// Durations shorter than a minute are shown in seconds.
+func humanDuration(d time.Duration) string {
+	d = max(d, 0).Round(time.Second)
+	if d < time.Minute {
+		return fmt.Sprintf("%d s", int(d.Seconds()))
+	}
+
+	d = d.Round(time.Minute)
+	hours, minutes := int(d.Hours()), int(d.Minutes())%60
+	switch {
+	case hours == 0:
+		return fmt.Sprintf("%d min", minutes)
+	case minutes == 0:
+		return fmt.Sprintf("%d h", hours)
+	}
+	return fmt.Sprintf("%d h %d min", hours, minutes)
+}
 
 func renderResults(results Play, output io.Writer) error {
-	if err := tmpl.Execute(output, results); err != nil {
+	loc, layout := timeZone, DefaultTimeFormat
+	if results.timeZone != nil {
+		loc = results.timeZone
+	}
+	if results.timeFormat != "" {
+		layout = results.timeFormat
+	}
+	// The parsed template is never executed itself so that it can be cloned
+	resultsTmpl := template.Must(tmpl.Clone()).Funcs(funcMap(loc, layout))
+
+	if err := resultsTmpl.Execute(output, results); err != nil {
 		return fmt.Errorf("rendering template: %w", err)
 	}
 
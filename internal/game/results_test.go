@@ -3,6 +3,7 @@ package game
 import (
 	_ "embed"
 	"os"
+	"strings"
 	"testing"
 	"time"
 )
@@ -34,6 +35,7 @@ func Test_renderResults(t *testing.T) {
 			},
 		},
 		StartedAt: time.Now().Add(-time.Minute*34 + -time.Second*12),
+		EndedAt:   time.Now(),
 	}
 
 	tests := []struct {
@@ -53,3 +55,55 @@ func Test_renderResults(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderResultsTimes(t *testing.T) {
+	started := time.Date(2024, 10, 18, 18, 2, 41, 0, time.UTC)
+	results := Play{
+		Panelists: []*Panelist{
+			{
+				Name: "Satan",
+				Song: &Song{
+					URL:          "https://example.com/satan_you_rock",
+					IntroducedAt: started.Add(10 * time.Minute),
+					RevealedAt:   started.Add(22*time.Minute + 20*time.Second),
+				},
+			},
+		},
+		StartedAt:  started,
+		EndedAt:    started.Add(83 * time.Minute),
+		timeZone:   time.FixedZone("EEST", 3*60*60),
+		timeFormat: "02.01.2006 15.04",
+	}
+
+	var out strings.Builder
+	if err := renderResults(results, &out); err != nil {
+		t.Fatalf("renderResults() error = %v", err)
+	}
+	for _, expected := range []string{
+		"Reviewing took:</strong> 12 min",
+		"Started at 18.10.2024 21.02, ended at 18.10.2024 22.25",
+		"Game took 1 h 23 min",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Results don't contain %q:\n%s", expected, out.String())
+		}
+	}
+}
+
+func TestHumanDuration(t *testing.T) {
+	tests := []struct {
+		want     string
+		duration time.Duration
+	}{
+		{duration: -time.Second, want: "0 s"},
+		{duration: 45*time.Second + 400*time.Millisecond, want: "45 s"},
+		{duration: 5*time.Minute + 29*time.Second, want: "5 min"},
+		{duration: 2 * time.Hour, want: "2 h"},
+		{duration: time.Hour + 23*time.Minute + 10*time.Second, want: "1 h 23 min"},
+	}
+	for _, tt := range tests {
+		if got := humanDuration(tt.duration); got != tt.want {
+			t.Errorf("humanDuration(%s) = %q, want %q", tt.duration, got, tt.want)
+		}
+	}
+}
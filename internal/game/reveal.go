@@ -0,0 +1,102 @@
+package game
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// RevealStyle controls how the reviews of a song are revealed.
+type RevealStyle int
+
+const (
+	// RevealConsolidated reveals the reviews and the score in a single message.
+	RevealConsolidated RevealStyle = iota
+	// RevealPaced reveals the reviews one at a time from the lowest rating
+	// to the highest and the score last, with a delay between the messages.
+	RevealPaced
+)
+
+// DefaultRevealDelay is the delay between the messages of a paced reveal.
+const DefaultRevealDelay = 5 * time.Second
+
+// ParseRevealStyle parses the style from its configuration value. An empty
+// value is the consolidated reveal.
+func ParseRevealStyle(style string) (RevealStyle, error) {
+	switch strings.ToLower(style) {
+	case "", "consolidated":
+		return RevealConsolidated, nil
+	case "paced":
+		return RevealPaced, nil
+	}
+	return RevealConsolidated, fmt.Errorf("unknown reveal style %q, expected consolidated or paced", style)
+}
+
+// WithReveal sets the reveal style and the delay between the messages
+// of a paced reveal.
+func WithReveal(style RevealStyle, delay time.Duration) PlayOption {
+	return func(p *Play) {
+		p.revealStyle = style
+		p.revealDelay = delay
+	}
+}
+
+// pacedReveal is the progress of a paced reveal. The game stays in
+// StateRevealReviews until the timers have revealed all the reviews.
+type pacedReveal struct {
+	cancel  func()
+	pending []*Review
+	done    bool
+}
+
+// renderReview formats the review for the channel.
+func (p *Play) renderReview(r *Review) richText {
+	from := bold(r.From)
+	if r.Audience {
+		from = p.tr(msgAudienceReviewer, from)
+	}
+	if r.Review == "" {
+		return p.tr(msgReview, from, p.tr(msgVoiceReview), r.Rating, p.ratingMax)
+	}
+	return p.tr(msgReview, from, r.Review, r.Rating, p.ratingMax)
+}
+
+// startPacedReveal reveals the lowest rated review and schedules the rest.
+func (p *Play) startPacedReveal() {
+	pending := slices.Clone(p.host.ReceivedReviews)
+	slices.SortStableFunc(pending, func(a, b *Review) int {
+		return cmp.Compare(a.Rating, b.Rating)
+	})
+	p.reveal = &pacedReveal{pending: pending, cancel: func() {}}
+	p.revealNext()
+}
+
+// revealNext reveals the next review, or finishes the reveal after the last
+// one. The game continues from the timer unless the reveal was finished
+// while handling a message, which happens when timers fire immediately.
+func (p *Play) revealNext() {
+	reveal := p.reveal
+	if len(reveal.pending) == 0 {
+		reveal.done = true
+		if !p.running {
+			p.resume()
+		}
+		return
+	}
+
+	review := reveal.pending[0]
+	reveal.pending = reveal.pending[1:]
+	p.sendMessageToChannel(p.renderReview(review))
+	reveal.cancel = p.after(p.revealDelay, p.revealNext)
+}
+
+// cancelReveal stops the paced reveal of a stopped game.
+func (p *Play) cancelReveal() {
+	if p.reveal == nil {
+		return
+	}
+	p.reveal.cancel()
+	p.reveal = nil
+}
@@ -0,0 +1,154 @@
+package game
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"weezel/jukeboxjury/internal/integration/telegram"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/go-cmp/cmp"
+)
+
+// manualTimers is a Scheduler whose timers fire when the test says so.
+type manualTimers struct {
+	pending []*func()
+}
+
+func (m *manualTimers) schedule(_ time.Duration, fn func()) func() {
+	timer := &fn
+	m.pending = append(m.pending, timer)
+	return func() { *timer = nil }
+}
+
+// fire fires the oldest timer which hasn't been cancelled.
+func (m *manualTimers) fire(t *testing.T) {
+	t.Helper()
+	for len(m.pending) > 0 {
+		timer := m.pending[0]
+		m.pending = m.pending[1:]
+		if *timer != nil {
+			(*timer)()
+			return
+		}
+	}
+	t.Fatal("No timer to fire")
+}
+
+func TestPacedReveal(t *testing.T) {
+	logDir := t.TempDir()
+
+	mockBot := mockTelegramBot{
+		mSend: func(_ tgbotapi.Chattable) (tgbotapi.Message, error) {
+			return tgbotapi.Message{}, nil
+		},
+		receivedMessages: []string{},
+	}
+	timers := &manualTimers{}
+	p := New(&mockBot, 1,
+		WithOutputDirectory(nil),
+		WithEventLog(logDir),
+		withoutShuffle(),
+		WithReveal(RevealPaced, time.Second),
+		withScheduler(timers.schedule),
+	)
+	handle := func(from *tgbotapi.User, text string) {
+		t.Helper()
+		update := testMessage(from, text)
+		msg, err := ParseToMessage(tgbotapi.Update{Message: &update})
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %#v", text, err)
+		}
+		p.Handle(msg)
+	}
+	lastMessage := func() string {
+		return mockBot.receivedMessages[len(mockBot.receivedMessages)-1]
+	}
+
+	santana := &tgbotapi.User{ID: 666, UserName: "Santana"}
+	jesus := &tgbotapi.User{ID: 123, UserName: "Jesus"}
+	pjotr := &tgbotapi.User{ID: 7, UserName: "Pjotr"}
+	handle(santana, "levyraati aloita")
+	handle(jesus, "levyraati liity")
+	handle(pjotr, "levyraati liity")
+	handle(santana, "levyraati jatka")
+	handle(santana, "levyraati esitä Song1 https://example.com/1")
+	handle(jesus, "levyraati esitä Song2 https://example.com/2")
+	handle(pjotr, "levyraati esitä Song3 https://example.com/3")
+	handle(jesus, "levyraati arvioi Great 9/10")
+	handle(pjotr, "levyraati arvioi Meh 2/10")
+
+	// The lowest rating is revealed first and the rest wait for the timers
+	if p.State() != StateRevealReviews {
+		t.Fatalf("State = %s, want %s", p.State(), StateRevealReviews)
+	}
+	if expected := "<b>Pjotr</b> wrote: Meh. The song rating was: 2/10"; lastMessage() != expected {
+		t.Errorf("First revealed %q, want %q", lastMessage(), expected)
+	}
+	sent := len(mockBot.receivedMessages)
+	handle(pjotr, "levyraati arvioi Changed my mind 10/10")
+	if len(mockBot.receivedMessages) != sent || p.State() != StateRevealReviews {
+		t.Errorf("Message during the reveal wasn't ignored, got %q", lastMessage())
+	}
+
+	timers.fire(t)
+	if expected := "<b>Jesus</b> wrote: Great. The song rating was: 9/10"; lastMessage() != expected {
+		t.Errorf("Second revealed %q, want %q", lastMessage(), expected)
+	}
+	timers.fire(t)
+	if p.State() != StateWaitForReviews {
+		t.Fatalf("State after the reveal = %s, want %s", p.State(), StateWaitForReviews)
+	}
+	expected := []string{
+		`Eventually the song <a href="https://example.com/1">Song1</a> ended up catching 5.50 points`,
+		`The next song comes from the panelist <b>Jesus</b>: <a href="https://example.com/2">Song2</a>`,
+	}
+	if diff := cmp.Diff(expected, mockBot.receivedMessages[sent+1:]); diff != "" {
+		t.Errorf("Messages after the reveal mismatch (-want +got):\n%s", diff)
+	}
+
+	// Stopping in the middle of the reveal cancels the rest of it
+	handle(santana, "levyraati arvioi Fine 5/10")
+	handle(pjotr, "levyraati arvioi Fine 6/10")
+	handle(santana, "levyraati lopeta")
+	if p.State() != StateInit {
+		t.Fatalf("State after stop = %s, want %s", p.State(), StateInit)
+	}
+	sent = len(mockBot.receivedMessages)
+	for _, timer := range timers.pending {
+		if *timer != nil {
+			t.Fatal("Timer of the stopped reveal wasn't cancelled")
+		}
+	}
+
+	logs, err := filepath.Glob(filepath.Join(logDir, "jukebox_jury_events_*.jsonl"))
+	if err != nil || len(logs) != 1 {
+		t.Fatalf("Expected a single event log, got %v: %v", logs, err)
+	}
+	fin, err := os.Open(logs[0])
+	if err != nil {
+		t.Fatalf("Failed to open event log: %v", err)
+	}
+	defer fin.Close()
+	events, err := ReadEvents(fin)
+	if err != nil {
+		t.Fatalf("Failed to read event log: %v", err)
+	}
+
+	var out bytes.Buffer
+	// Replay plays with the logged reveal style
+	if _, err = Replay(events, telegram.NewFakeBot(&out), WithOutputDirectory(nil), withoutShuffle()); err != nil {
+		t.Fatalf("Replay diverged: %v", err)
+	}
+	replayed := []string{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		replayed = append(replayed, chatPrefix.ReplaceAllString(line, ""))
+	}
+	if diff := cmp.Diff(mockBot.receivedMessages[:sent], replayed); diff != "" {
+		t.Errorf("Replayed messages mismatch (-want +got):\n%s", diff)
+	}
+}
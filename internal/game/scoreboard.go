@@ -0,0 +1,148 @@
+package game
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"weezel/jukeboxjury/internal/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// WithScoreboard enables the scoreboard message, which is pinned in the
+// channel and edited as the game progresses. The scoreboard replaces the
+// messages about joined panelists, added songs and given reviews.
+func WithScoreboard(enabled bool) PlayOption {
+	return func(p *Play) {
+		p.scoreboard = enabled
+	}
+}
+
+// announceProgress tells the channel about progress shown on the scoreboard,
+// when there's no scoreboard.
+func (p *Play) announceProgress(text richText) {
+	if p.scoreboard {
+		return
+	}
+	p.sendMessageToChannel(text)
+}
+
+// progressMark tells how far the participant is in the current phase.
+func (p *Play) progressMark(participant *Panelist) string {
+	switch p.state {
+	case StateAddSong:
+		if !participant.SongSubmitted && !participant.audience {
+			return "⏳"
+		}
+	case StateIntroduceSong, StateWaitForCommentary, StateWaitForReviews, StateRevealReviews:
+		if participant == p.host {
+			return "🎤"
+		}
+		if !participant.ReviewGiven {
+			return "⏳"
+		}
+	case StateInit, StateStartGame, StateWaitPanelistsToJoin, StateShuffleHost, StateStopGame,
+		StateStartTournament, StateIntroduceMatch, StateWaitForVotes, StateDecideMatch:
+	}
+	return "✅"
+}
+
+// renderScoreboard lists the participants with their progress and
+// the ranking of the songs revealed so far.
+func (p *Play) renderScoreboard(title richText) richText {
+	var sb strings.Builder
+	sb.WriteString("<b>" + string(title) + "</b>")
+
+	sb.WriteString("\n\n" + string(p.tr(msgScoreboardPanel)))
+	for _, panelist := range p.Panelists {
+		fmt.Fprintf(&sb, "\n%s %s", p.progressMark(panelist), escape(panelist.Name))
+	}
+	for _, juror := range p.AudienceJurors {
+		fmt.Fprintf(&sb, "\n%s %s", p.progressMark(juror), p.tr(msgAudienceReviewer, juror.Name))
+	}
+
+	var revealed []*Panelist
+	for _, panelist := range p.Panelists {
+		if panelist.Song != nil && !panelist.Song.RevealedAt.IsZero() {
+			revealed = append(revealed, panelist)
+		}
+	}
+	if len(revealed) == 0 {
+		return richText(sb.String())
+	}
+	slices.SortStableFunc(revealed, func(a, b *Panelist) int {
+		return cmp.Compare(b.Song.AverageScore, a.Song.AverageScore)
+	})
+	sb.WriteString("\n\n" + string(p.tr(msgScoreboardRank)))
+	for i, panelist := range revealed {
+		fmt.Fprintf(&sb, "\n%d. %s %.2f", i+1, escape(panelist.Name), panelist.Song.AverageScore)
+	}
+
+	return richText(sb.String())
+}
+
+// refreshScoreboard posts the scoreboard of a new game, or edits it to
+// match the game.
+func (p *Play) refreshScoreboard() {
+	// Tournament nights have no panel to show
+	if !p.scoreboard || p.state == StateInit || p.bracket != nil {
+		return
+	}
+	if p.scoreboardID == 0 {
+		p.openScoreboard()
+		return
+	}
+	p.editScoreboard(p.renderScoreboard(p.tr(msgScoreboardTitle, p.describeState(p.state))))
+}
+
+// openScoreboard posts and pins the scoreboard.
+func (p *Play) openScoreboard() {
+	p.scoreboardText = p.renderScoreboard(p.tr(msgScoreboardTitle, p.describeState(p.state)))
+	sent, err := p.bot.Send(newMessage(p.chatID, p.scoreboardText, false))
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Error sending scoreboard")
+		return
+	}
+	p.scoreboardID = sent.MessageID
+
+	pin := tgbotapi.PinChatMessageConfig{
+		ChatID:              p.chatID,
+		MessageID:           sent.MessageID,
+		DisableNotification: true,
+	}
+	if _, err = p.bot.Request(pin); err != nil {
+		logger.Logger.Warn().Err(err).Msg("Couldn't pin scoreboard, is the bot an admin?")
+	}
+}
+
+// closeScoreboard shows the final ranking and unpins the scoreboard.
+func (p *Play) closeScoreboard() {
+	if p.scoreboardID == 0 {
+		return
+	}
+
+	p.editScoreboard(p.renderScoreboard(p.tr(msgScoreboardEnded)))
+	unpin := tgbotapi.UnpinChatMessageConfig{ChatID: p.chatID, MessageID: p.scoreboardID}
+	if _, err := p.bot.Request(unpin); err != nil {
+		logger.Logger.Warn().Err(err).Msg("Couldn't unpin scoreboard")
+	}
+	p.scoreboardID = 0
+	p.scoreboardText = ""
+}
+
+func (p *Play) editScoreboard(text richText) {
+	// Telegram refuses edits which don't change the message
+	if text == p.scoreboardText {
+		return
+	}
+	edit := tgbotapi.NewEditMessageText(p.chatID, p.scoreboardID, string(text))
+	edit.ParseMode = tgbotapi.ModeHTML
+	edit.DisableWebPagePreview = true
+	if _, err := p.bot.Request(edit); err != nil {
+		logger.Logger.Error().Err(err).Str("payload", string(text)).Msg("Error editing scoreboard")
+		return
+	}
+	p.scoreboardText = text
+}
@@ -0,0 +1,81 @@
+package game
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestScoreboard(t *testing.T) {
+
+	mockBot := mockTelegramBot{
+		mSend: func(_ tgbotapi.Chattable) (tgbotapi.Message, error) {
+			return tgbotapi.Message{MessageID: 42}, nil
+		},
+		receivedMessages: []string{},
+	}
+
+	santana := &tgbotapi.User{ID: 666, UserName: "Santana"}
+	jesus := &tgbotapi.User{ID: 123, UserName: "Jesus"}
+	updates := []tgbotapi.Message{
+		testMessage(santana, "levyraati aloita"),
+		testMessage(jesus, "levyraati liity"),
+		testMessage(santana, "levyraati jatka"),
+		testMessage(santana, "levyraati esitä Song1 https://example.com/1"),
+		testMessage(jesus, "levyraati esitä Song2 https://example.com/2"),
+		testMessage(jesus, "levyraati arvioi Good 8/10"),
+		testMessage(santana, "levyraati arvioi Bad 3/10"),
+	}
+
+	p := New(&mockBot, 1, WithOutputDirectory(nil), withoutShuffle(), WithScoreboard(true))
+	for i, update := range updates {
+		msg, err := ParseToMessage(tgbotapi.Update{Message: &update})
+		if err != nil {
+			t.Fatalf("Failed to parse %d %q: %#v", i, update.Text, err)
+		}
+		p.Handle(msg)
+	}
+
+	for _, text := range mockBot.receivedMessages {
+		if strings.Contains(text, "joined the game") || strings.HasPrefix(text, "Panelist ") {
+			t.Errorf("Progress message %q sent although the scoreboard shows it", text)
+		}
+	}
+	scoreboard := "<b>Jukebox Jury: Game is waiting for panelists to join</b>\n\nPanelists:\n✅ Santana"
+	if !slices.Contains(mockBot.receivedMessages, scoreboard) {
+		t.Errorf("Scoreboard %q wasn't posted, got %q", scoreboard, mockBot.receivedMessages)
+	}
+
+	var requests []string
+	for _, request := range mockBot.requests {
+		switch r := request.(type) {
+		case tgbotapi.PinChatMessageConfig:
+			requests = append(requests, "pin")
+		case tgbotapi.UnpinChatMessageConfig:
+			requests = append(requests, "unpin")
+		case tgbotapi.EditMessageTextConfig:
+			if r.MessageID != 42 {
+				t.Errorf("Edited message %d, want the scoreboard 42", r.MessageID)
+			}
+			requests = append(requests, r.Text)
+		}
+	}
+	expected := []string{
+		"pin",
+		"<b>Jukebox Jury: Game is waiting for panelists to join</b>\n\nPanelists:\n✅ Santana\n✅ Jesus",
+		"<b>Jukebox Jury: Game is waiting for songs</b>\n\nPanelists:\n⏳ Santana\n⏳ Jesus",
+		"<b>Jukebox Jury: Game is waiting for songs</b>\n\nPanelists:\n✅ Santana\n⏳ Jesus",
+		"<b>Jukebox Jury: Game is reviewing songs</b>\n\nPanelists:\n🎤 Santana\n⏳ Jesus",
+		"<b>Jukebox Jury: Game is reviewing songs</b>\n\nPanelists:\n⏳ Santana\n🎤 Jesus\n\n" +
+			"Ranking:\n1. Santana 8.00",
+		"<b>Jukebox Jury: Game has ended</b>\n\nPanelists:\n✅ Santana\n✅ Jesus\n\n" +
+			"Ranking:\n1. Santana 8.00\n2. Jesus 3.00",
+		"unpin",
+	}
+	if diff := cmp.Diff(expected, requests); diff != "" {
+		t.Errorf("Scoreboard requests mismatch (-want +got):\n%s", diff)
+	}
+}
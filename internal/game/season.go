@@ -0,0 +1,113 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"weezel/jukeboxjury/internal/tournament"
+)
+
+// songResult is a song of a finished game in the JSON results.
+type songResult struct {
+	Song       *Song     `json:"song"`
+	Panelist   string    `json:"panelist"`
+	Reviews    []*Review `json:"reviews"`
+	PanelistID int64     `json:"panelist_id"`
+}
+
+// songResults is the machine readable copy of the game results.
+type songResults struct {
+	StartedAt   time.Time    `json:"started_at"`
+	EndedAt     time.Time    `json:"ended_at"`
+	Songs       []songResult `json:"songs"`
+	Interrupted bool         `json:"interrupted"`
+}
+
+// writeSongResults writes the results of the game as JSON.
+func (p *Play) writeSongResults(output io.Writer) error {
+	results := songResults{
+		StartedAt:   p.StartedAt,
+		EndedAt:     p.EndedAt,
+		Songs:       make([]songResult, 0, len(p.Panelists)),
+		Interrupted: p.interrupted,
+	}
+	for _, panelist := range p.Panelists {
+		results.Songs = append(results.Songs, songResult{
+			Song:       panelist.Song,
+			Panelist:   panelist.Name,
+			Reviews:    panelist.ReceivedReviews,
+			PanelistID: panelist.uid,
+		})
+	}
+	// Songs of the earlier elimination rounds were replaced by the next ones
+	for _, round := range p.EliminationRounds {
+		for _, result := range round.Songs {
+			current := slices.ContainsFunc(p.Panelists, func(pan *Panelist) bool {
+				return pan.Song == result.Song
+			})
+			if !current {
+				results.Songs = append(results.Songs, result)
+			}
+		}
+	}
+
+	encoder := json.NewEncoder(output)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(results); err != nil {
+		return fmt.Errorf("encoding results: %w", err)
+	}
+	return nil
+}
+
+// LoadSeason reads the songs of the previous games from the JSON results
+// in the directory. Only the songs whose reviews were revealed are
+// returned, a song played more than once by its best score.
+func LoadSeason(directory string) ([]tournament.Entry, error) {
+	fpaths, err := filepath.Glob(filepath.Join(directory, "jukebox_jury_results_*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("listing results: %w", err)
+	}
+
+	entries := []tournament.Entry{}
+	byURL := map[string]int{}
+	for _, fpath := range fpaths {
+		content, err := os.ReadFile(fpath)
+		if err != nil {
+			return nil, fmt.Errorf("file %q read: %w", fpath, err)
+		}
+		var results songResults
+		if err = json.Unmarshal(content, &results); err != nil {
+			return nil, fmt.Errorf("file %q parse: %w", fpath, err)
+		}
+
+		for _, result := range results.Songs {
+			song := result.Song
+			if song == nil || song.RevealedAt.IsZero() {
+				continue
+			}
+			entry := tournament.Entry{
+				PlayedAt:    song.RevealedAt,
+				Panelist:    result.Panelist,
+				Description: song.Description,
+				URL:         song.URL,
+				Score:       song.AverageScore,
+				PanelistID:  result.PanelistID,
+			}
+			i, found := byURL[song.URL]
+			switch {
+			case !found:
+				byURL[song.URL] = len(entries)
+				entries = append(entries, entry)
+			case entry.Score > entries[i].Score:
+				entries[i] = entry
+			}
+		}
+	}
+
+	return entries, nil
+}
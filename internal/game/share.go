@@ -0,0 +1,111 @@
+package game
+
+import (
+	"errors"
+
+	"weezel/jukeboxjury/internal/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Commands of songs shared as plain links in a private chat. They aren't
+// typed by the users, the parser and the confirmation buttons produce them.
+const (
+	commandShareSong   = "share-song"
+	commandConfirmSong = "confirm-song"
+	commandCancelSong  = "cancel-song"
+)
+
+var ErrUnknownCallback = errors.New("unknown callback data")
+
+// parseCallback parses a press of the confirmation buttons.
+func parseCallback(cq *tgbotapi.CallbackQuery) (Message, error) {
+	if cq.Data != commandConfirmSong && cq.Data != commandCancelSong {
+		return Message{}, ParseError{Err: ErrUnknownCallback}
+	}
+	if cq.From == nil {
+		return Message{}, ParseError{Err: ErrNoSender}
+	}
+
+	msg := Message{
+		Command:    cq.Data,
+		CallbackID: cq.ID,
+		PlayerName: cq.From.UserName,
+		FromID:     cq.From.ID,
+		ChatID:     cq.From.ID,
+	}
+	if msg.PlayerName == "" {
+		msg.PlayerName = cq.From.FirstName
+	}
+	if cq.Message != nil && cq.Message.Chat != nil {
+		msg.ChatID = cq.Message.Chat.ID
+	}
+
+	return msg, nil
+}
+
+// offerSharedSong asks the panelist to confirm the song shared as a link.
+func (p *Play) offerSharedSong(msg Message) {
+	panelist := p.findReviewer(msg.FromID)
+	if panelist == nil || panelist.audience {
+		p.sendMessageToPanelist(msg.ChatID, p.tr(msgNotInGame))
+		return
+	}
+	if panelist.SongSubmitted {
+		p.sendMessageToPanelist(msg.ChatID, p.tr(msgSongAlreadyAdded))
+		return
+	}
+	song, err := parseSong(msg)
+	if err != nil {
+		logger.Logger.Warn().Err(err).Interface("msg", msg).Msg("Couldn't parse shared song")
+		p.sendMessageToPanelist(msg.ChatID, p.tr(msgMalformedSong))
+		return
+	}
+
+	p.sharedSongs[msg.FromID] = msg
+	// The preview lets the panelist check the song before presenting it
+	reply := newMessage(msg.ChatID, p.tr(msgConfirmShare, song.Description, song.URL), true)
+	reply.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(p.tr(msgButtonPresent).plain(), commandConfirmSong),
+		tgbotapi.NewInlineKeyboardButtonData(p.tr(msgButtonCancel).plain(), commandCancelSong),
+	))
+	if _, err = p.bot.Send(reply); err != nil {
+		logger.Logger.Error().Err(err).Msg("Error sending shared song confirmation")
+	}
+}
+
+// confirmSharedSong returns the shared song of the sender as a command
+// to present it. Returns false when there's nothing to confirm.
+func (p *Play) confirmSharedSong(msg Message) (Message, bool) {
+	shared, found := p.sharedSongs[msg.FromID]
+	if !found {
+		p.answerCallback(msg, p.tr(msgNothingToConfirm))
+		return Message{}, false
+	}
+	delete(p.sharedSongs, msg.FromID)
+	p.answerCallback(msg, p.tr(msgSharePresented))
+
+	shared.Command = CommandPresent
+	return shared, true
+}
+
+func (p *Play) cancelSharedSong(msg Message) {
+	if _, found := p.sharedSongs[msg.FromID]; !found {
+		p.answerCallback(msg, p.tr(msgNothingToConfirm))
+		return
+	}
+	delete(p.sharedSongs, msg.FromID)
+	p.answerCallback(msg, p.tr(msgShareCancelled))
+}
+
+// answerCallback answers the button press, or sends the text as a message
+// when the command was typed.
+func (p *Play) answerCallback(msg Message, text richText) {
+	if msg.CallbackID == "" {
+		p.sendMessageToPanelist(msg.ChatID, text)
+		return
+	}
+	if _, err := p.bot.Request(tgbotapi.NewCallback(msg.CallbackID, text.plain())); err != nil {
+		logger.Logger.Error().Err(err).Str("payload", string(text)).Msg("Error answering callback")
+	}
+}
@@ -0,0 +1,119 @@
+package game
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/go-cmp/cmp"
+)
+
+func sharedLink(from *tgbotapi.User, text, link string) tgbotapi.Update {
+	return tgbotapi.Update{Message: &tgbotapi.Message{
+		Chat: &tgbotapi.Chat{ID: from.ID, Type: "private"},
+		From: from,
+		Text: text + " " + link,
+		Entities: []tgbotapi.MessageEntity{
+			{Type: "url", Offset: len(text) + 1, Length: len(link)},
+		},
+	}}
+}
+
+func buttonPress(from *tgbotapi.User, data string) tgbotapi.Update {
+	return tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{
+		ID:      "callback-" + data,
+		From:    from,
+		Message: &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: from.ID, Type: "private"}},
+		Data:    data,
+	}}
+}
+
+func TestSharedSongs(t *testing.T) {
+
+	mockBot := mockTelegramBot{
+		mSend: func(_ tgbotapi.Chattable) (tgbotapi.Message, error) {
+			return tgbotapi.Message{}, nil
+		},
+		receivedMessages: []string{},
+	}
+
+	santana := &tgbotapi.User{ID: 666, UserName: "Santana"}
+	jesus := &tgbotapi.User{ID: 123, UserName: "Jesus"}
+	stranger := &tgbotapi.User{ID: 7, UserName: "Stranger"}
+
+	p := New(&mockBot, 1, WithOutputDirectory(nil), withoutShuffle())
+	updates := []tgbotapi.Update{
+		// Links shared before adding the songs are ignored
+		sharedLink(santana, "Too early", "https://example.com/early"),
+		{Message: ptr(testMessage(santana, "levyraati aloita"))},
+		{Message: ptr(testMessage(jesus, "levyraati liity"))},
+		{Message: ptr(testMessage(santana, "levyraati jatka"))},
+		sharedLink(stranger, "Not playing", "https://example.com/stranger"),
+		buttonPress(santana, commandConfirmSong),
+		sharedLink(santana, "Listen to this", "https://example.com/santana"),
+		buttonPress(santana, commandConfirmSong),
+		sharedLink(jesus, "Hallelujah", "https://example.com/jesus"),
+		buttonPress(jesus, commandCancelSong),
+	}
+	for i, update := range updates {
+		msg, err := ParseToMessage(update)
+		if err != nil {
+			t.Fatalf("Failed to parse %d: %v", i, err)
+		}
+		p.Handle(msg)
+	}
+
+	if p.State() != StateAddSong {
+		t.Errorf("State = %s, want %s", p.State(), StateAddSong)
+	}
+	if song := p.Panelists[0].Song; song == nil || song.URL != "https://example.com/santana" ||
+		song.Description != "Listen to this" {
+		t.Errorf("Santana's song = %+v, want the shared one", song)
+	}
+	if p.Panelists[1].SongSubmitted {
+		t.Errorf("Jesus' cancelled song was submitted")
+	}
+
+	expected := []string{
+		"User <b>Santana</b> started a new game, join by using command: levyraati liity",
+		"User <b>Jesus</b> joined the game",
+		"User <b>Santana</b> wants to proceed, continuing...",
+		"Add song with the following command and format in private chat with the bot: " +
+			"levyraati esitä description here https://link-as-last-item",
+		"Add review similar way (max score is 10, only integers): " +
+			"levyraati arvioi description here 0/10",
+		"You are not in the game, join the next one",
+		"Present this song? Description: Listen to this, URL: https://example.com/santana",
+		"Panelist <b>Santana</b> added a song",
+		"Present this song? Description: Hallelujah, URL: https://example.com/jesus",
+	}
+	if diff := cmp.Diff(expected, mockBot.receivedMessages); diff != "" {
+		t.Errorf("Messages mismatch (-want +got):\n%s", diff)
+	}
+
+	var answers []string
+	for _, request := range mockBot.requests {
+		if callback, ok := request.(tgbotapi.CallbackConfig); ok {
+			answers = append(answers, callback.Text)
+		}
+	}
+	expectedAnswers := []string{
+		"There is no song waiting for confirmation",
+		"Song presented",
+		"Song wasn't presented",
+	}
+	if diff := cmp.Diff(expectedAnswers, answers); diff != "" {
+		t.Errorf("Callback answers mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSharedLinkOutsidePrivateChat(t *testing.T) {
+	update := sharedLink(&tgbotapi.User{ID: 1}, "Listen", "https://example.com")
+	update.Message.Chat.Type = "group"
+	if _, err := ParseToMessage(update); err == nil {
+		t.Error("Parse() of a link in a group succeeded, want an error")
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,78 @@
+package game
+
+import (
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"weezel/jukeboxjury/internal/logger"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// linkPreviewOptions are the link_preview_options of the Bot API, which
+// the Telegram library doesn't support yet.
+type linkPreviewOptions struct {
+	URL              string `json:"url"`
+	PreferLargeMedia bool   `json:"prefer_large_media"`
+}
+
+// webURL parses the URL of a web page, which Telegram can link to and
+// preview.
+func webURL(s string) (*url.URL, bool) {
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, false
+	}
+	return u, true
+}
+
+// isAudioFile tells whether the URL points to an audio file which Telegram
+// can send as audio. Telegram accepts only MP3 and M4A files by URL.
+func isAudioFile(u *url.URL) bool {
+	switch strings.ToLower(path.Ext(u.Path)) {
+	case ".mp3", ".m4a":
+		return true
+	}
+	return false
+}
+
+// sendSongToChannel introduces the song so that it can be played right
+// from the chat. Audio files are sent as audio, and the rest as a message
+// with a large preview of the song.
+func (p *Play) sendSongToChannel(song *Song, text richText) {
+	u, ok := webURL(song.URL)
+	if !ok {
+		p.sendMessageToChannel(text)
+		return
+	}
+
+	if isAudioFile(u) {
+		audio := tgbotapi.NewAudio(p.chatID, tgbotapi.FileURL(u.String()))
+		audio.Caption = string(text)
+		audio.ParseMode = tgbotapi.ModeHTML
+		audio.Title = song.Description
+		_, err := p.bot.Send(audio)
+		if err == nil {
+			return
+		}
+		// Telegram refuses files which are too large or can't be downloaded
+		logger.Logger.Warn().Err(err).Str("song_url", song.URL).Msg("Couldn't send the song as audio")
+	}
+
+	params := tgbotapi.Params{
+		"chat_id":    strconv.FormatInt(p.chatID, 10),
+		"text":       string(text),
+		"parse_mode": tgbotapi.ModeHTML,
+	}
+	preview := linkPreviewOptions{URL: u.String(), PreferLargeMedia: true}
+	if err := params.AddInterface("link_preview_options", preview); err != nil {
+		logger.Logger.Error().Err(err).Msg("Error encoding link preview options")
+		p.sendMessageToChannel(text)
+		return
+	}
+	if _, err := p.bot.MakeRequest("sendMessage", params); err != nil {
+		logger.Logger.Error().Err(err).Str("payload", string(text)).Msg("Error sending song introduction")
+	}
+}
@@ -0,0 +1,87 @@
+package game
+
+import (
+	"errors"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSendSongToChannel(t *testing.T) {
+	tests := []struct {
+		name        string
+		url         string
+		audioErr    error
+		audio       string // URL of the sent audio
+		preview     string // Link preview options of the sent message
+		plainSentTo int64  // Chat of the plain message
+	}{
+		{
+			name:    "Web page gets a large preview",
+			url:     "https://example.com/song",
+			preview: `{"url":"https://example.com/song","prefer_large_media":true}`,
+		},
+		{
+			name:  "Audio file is sent as audio",
+			url:   "https://example.com/song.MP3",
+			audio: "https://example.com/song.MP3",
+		},
+		{
+			name:     "Audio file refused by Telegram gets a preview",
+			url:      "https://example.com/song.m4a",
+			audioErr: errors.New("file is too big"),
+			preview:  `{"url":"https://example.com/song.m4a","prefer_large_media":true}`,
+		},
+		{
+			name:        "Not a web link",
+			url:         "spotify:track:1",
+			plainSentTo: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var audio string
+			var plainSentTo int64
+			mockBot := mockTelegramBot{
+				mSend: func(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+					switch msg := c.(type) {
+					case tgbotapi.AudioConfig:
+						if tt.audioErr != nil {
+							return tgbotapi.Message{}, tt.audioErr
+						}
+						audio = string(msg.File.(tgbotapi.FileURL))
+						if msg.Title != "Song" || msg.ParseMode != tgbotapi.ModeHTML {
+							t.Errorf("Audio title = %q, parse mode = %q",
+								msg.Title, msg.ParseMode)
+						}
+					case tgbotapi.MessageConfig:
+						plainSentTo = msg.ChatID
+					}
+					return tgbotapi.Message{}, nil
+				},
+				receivedMessages: []string{},
+			}
+			p := New(&mockBot, 1, WithOutputDirectory(nil))
+
+			p.sendSongToChannel(&Song{Description: "Song", URL: tt.url}, "The next song")
+
+			if audio != tt.audio {
+				t.Errorf("Sent audio %q, want %q", audio, tt.audio)
+			}
+			if plainSentTo != tt.plainSentTo {
+				t.Errorf("Plain message sent to %d, want %d", plainSentTo, tt.plainSentTo)
+			}
+			var preview string
+			if len(mockBot.madeRequests) > 0 {
+				preview = mockBot.madeRequests[0]["link_preview_options"]
+			}
+			if diff := cmp.Diff(tt.preview, preview); diff != "" {
+				t.Errorf("Link preview options mismatch (-want +got):\n%s", diff)
+			}
+			if sent := mockBot.receivedMessages; sent[len(sent)-1] != "The next song" {
+				t.Errorf("Messages %q don't end with the introduction", sent)
+			}
+		})
+	}
+}
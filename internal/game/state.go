@@ -0,0 +1,288 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	"weezel/jukeboxjury/internal/logger"
+)
+
+//go:generate go run ../../cmd/statediagram -readme ../../README.md
+
+// State is a state of the game. Transitions between the states
+// are declared in the Transitions table.
+type State int
+
+const (
+	StateInit State = iota
+	StateStartGame
+	StateWaitPanelistsToJoin
+	StateAddSong
+	StateShuffleHost
+	StateIntroduceSong
+	StateWaitForCommentary
+	StateWaitForReviews
+	StateRevealReviews
+	StateStopGame
+	StateStartTournament
+	StateIntroduceMatch
+	StateWaitForVotes
+	StateDecideMatch
+)
+
+var stateNames = map[State]string{
+	StateInit:                "Init",
+	StateStartGame:           "StartGame",
+	StateWaitPanelistsToJoin: "WaitPanelistsToJoin",
+	StateAddSong:             "AddSong",
+	StateShuffleHost:         "ShuffleHost",
+	StateIntroduceSong:       "IntroduceSong",
+	StateWaitForCommentary:   "WaitForCommentary",
+	StateWaitForReviews:      "WaitForReviews",
+	StateRevealReviews:       "RevealReviews",
+	StateStopGame:            "StopGame",
+	StateStartTournament:     "StartTournament",
+	StateIntroduceMatch:      "IntroduceMatch",
+	StateWaitForVotes:        "WaitForVotes",
+	StateDecideMatch:         "DecideMatch",
+}
+
+func (s State) String() string {
+	if name, found := stateNames[s]; found {
+		return name
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// ParseState returns the state with the given name.
+func ParseState(name string) (State, error) {
+	for state, stateName := range stateNames {
+		if stateName == name {
+			return state, nil
+		}
+	}
+	return StateInit, fmt.Errorf("unknown state %q", name)
+}
+
+func (s State) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+func (s *State) UnmarshalText(text []byte) error {
+	state, err := ParseState(string(text))
+	if err != nil {
+		return err
+	}
+	*s = state
+	return nil
+}
+
+// waitsForInput tells whether the state waits for the next message.
+// The other states are passed through immediately once entered.
+func (s State) waitsForInput() bool {
+	switch s {
+	case StateInit, StateWaitPanelistsToJoin, StateAddSong, StateWaitForCommentary, StateWaitForReviews,
+		StateWaitForVotes:
+		return true
+	case StateStartGame, StateShuffleHost, StateIntroduceSong, StateRevealReviews, StateStopGame,
+		StateStartTournament, StateIntroduceMatch, StateDecideMatch:
+	}
+	return false
+}
+
+// Transition is an allowed change from one state to another.
+type Transition struct {
+	Label string
+	From  State
+	To    State
+}
+
+// Transitions lists every allowed state change. The state diagram
+// in README.md is generated from this table.
+var Transitions = []Transition{
+	{From: StateInit, To: StateStartGame, Label: "Panelist starts the game"},
+	{From: StateStartGame, To: StateWaitPanelistsToJoin, Label: "Waiting for panelists"},
+	{From: StateWaitPanelistsToJoin, To: StateWaitPanelistsToJoin, Label: "Add panelist"},
+	{From: StateWaitPanelistsToJoin, To: StateAddSong, Label: "All panelists joined"},
+	{From: StateWaitPanelistsToJoin, To: StateInit, Label: "Game stopped"},
+	{From: StateAddSong, To: StateAddSong, Label: "Wait for a new song"},
+	{From: StateAddSong, To: StateShuffleHost, Label: "All songs submitted"},
+	{From: StateAddSong, To: StateInit, Label: "Game stopped"},
+	{From: StateShuffleHost, To: StateIntroduceSong, Label: "Introduce a song"},
+	{From: StateIntroduceSong, To: StateWaitForReviews, Label: "Collecting reviews"},
+	{From: StateIntroduceSong, To: StateWaitForCommentary, Label: "Host commentary enabled"},
+	{From: StateIntroduceSong, To: StateStopGame, Label: "No contestants left"},
+	{From: StateWaitForCommentary, To: StateWaitForCommentary, Label: "Wait for the host's commentary"},
+	{From: StateWaitForCommentary, To: StateWaitForReviews, Label: "Commentary posted or skipped"},
+	{From: StateWaitForCommentary, To: StateIntroduceSong, Label: "Host kicked"},
+	{From: StateWaitForCommentary, To: StateStopGame, Label: "Last host kicked"},
+	{From: StateWaitForCommentary, To: StateAddSong, Label: "Last host of the round kicked"},
+	{From: StateWaitForCommentary, To: StateInit, Label: "Game stopped"},
+	{From: StateWaitForReviews, To: StateWaitForReviews, Label: "Wait for reviews"},
+	{From: StateWaitForReviews, To: StateRevealReviews, Label: "All reviews submitted"},
+	{From: StateWaitForReviews, To: StateIntroduceSong, Label: "Song skipped"},
+	{From: StateWaitForReviews, To: StateStopGame, Label: "Last song skipped"},
+	{From: StateWaitForReviews, To: StateAddSong, Label: "Last song of the round skipped"},
+	{From: StateWaitForReviews, To: StateInit, Label: "Game stopped"},
+	{From: StateRevealReviews, To: StateIntroduceSong, Label: "Next song from the list"},
+	{From: StateRevealReviews, To: StateStopGame, Label: "All songs reviewed"},
+	{From: StateRevealReviews, To: StateAddSong, Label: "Lowest scorer eliminated"},
+	{From: StateRevealReviews, To: StateInit, Label: "Game stopped"},
+	{From: StateStopGame, To: StateInit, Label: "Wait for a new game"},
+	{From: StateInit, To: StateStartTournament, Label: "Panelist starts a tournament night"},
+	{From: StateStartTournament, To: StateIntroduceMatch, Label: "Bracket loaded"},
+	{From: StateStartTournament, To: StateInit, Label: "No tournament to play"},
+	{From: StateIntroduceMatch, To: StateWaitForVotes, Label: "Collecting votes"},
+	{From: StateIntroduceMatch, To: StateInit, Label: "Round finished or champion decided"},
+	{From: StateWaitForVotes, To: StateWaitForVotes, Label: "Wait for votes"},
+	{From: StateWaitForVotes, To: StateDecideMatch, Label: "Match closed"},
+	{From: StateWaitForVotes, To: StateInit, Label: "Game stopped"},
+	{From: StateDecideMatch, To: StateIntroduceMatch, Label: "Next match"},
+}
+
+func isAllowedTransition(from State, to State) bool {
+	for _, t := range Transitions {
+		if t.From == from && t.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Hook is called when the game enters or exits a state.
+type Hook func(from State, to State)
+
+// WithOnEnter registers a hook which is called after entering the state.
+func WithOnEnter(state State, hook Hook) PlayOption {
+	return func(p *Play) {
+		p.onEnter[state] = append(p.onEnter[state], hook)
+	}
+}
+
+// WithOnExit registers a hook which is called before exiting the state.
+func WithOnExit(state State, hook Hook) PlayOption {
+	return func(p *Play) {
+		p.onExit[state] = append(p.onExit[state], hook)
+	}
+}
+
+// State returns the current state of the game.
+func (p *Play) State() State {
+	return p.state
+}
+
+// handler returns the function which handles messages in the state.
+func (p *Play) handler(state State) func(msg Message) State {
+	switch state {
+	case StateInit:
+		return p.init
+	case StateStartGame:
+		return p.startGame
+	case StateWaitPanelistsToJoin:
+		return p.waitPanelistsToJoin
+	case StateAddSong:
+		return p.addSongs
+	case StateShuffleHost:
+		return p.shuffleHost
+	case StateIntroduceSong:
+		return p.introduceSong
+	case StateWaitForCommentary:
+		return p.waitForCommentary
+	case StateWaitForReviews:
+		return p.waitForReviews
+	case StateRevealReviews:
+		return p.revealReviews
+	case StateStopGame:
+		return p.stopGame
+	case StateStartTournament:
+		return p.startTournament
+	case StateIntroduceMatch:
+		return p.introduceMatch
+	case StateWaitForVotes:
+		return p.waitForVotes
+	case StateDecideMatch:
+		return p.decideMatch
+	}
+	return nil
+}
+
+// Handle feeds the message to the current state and follows the transitions
+// until the game reaches a state which waits for the next message.
+func (p *Play) Handle(msg Message) {
+	msg.Command = p.resolveCommand(msg.Command)
+	if p.state == StateInit && (msg.Command == CommandStart || msg.Command == CommandTour && p.tournaments) {
+		p.openEventLog()
+	}
+	p.recordEvent(Event{Type: EventMessage, Message: &msg})
+	defer p.refreshScoreboard()
+
+	if p.handleGlobalCommand(msg) {
+		return
+	}
+	p.run(msg)
+}
+
+// resume continues the game from a timer, e.g. when a paced reveal has
+// revealed every review.
+func (p *Play) resume() {
+	defer p.refreshScoreboard()
+	p.run(Message{})
+}
+
+// run follows the transitions until the game reaches a state which waits
+// for the next message.
+func (p *Play) run(msg Message) {
+	p.running = true
+	defer func() { p.running = false }()
+
+	for {
+		next := p.handler(p.state)(msg)
+		if next == p.state && p.waitsForInput() {
+			return
+		}
+		if err := p.transition(next); err != nil {
+			logger.Logger.Error().Err(err).Interface("msg", msg).Msg("Invalid state transition")
+			return
+		}
+		if p.waitsForInput() {
+			return
+		}
+	}
+}
+
+// waitsForInput tells whether the game waits for the next message
+// or a timer.
+func (p *Play) waitsForInput() bool {
+	return p.state.waitsForInput() || (p.state == StateRevealReviews && p.reveal != nil)
+}
+
+// transition moves the game into the given state and calls the hooks.
+func (p *Play) transition(to State) error {
+	from := p.state
+	if !isAllowedTransition(from, to) {
+		return fmt.Errorf("transition from %s to %s is not allowed", from, to)
+	}
+
+	logger.Logger.Debug().Msgf("State: %s -> %s", from, to)
+	for _, hook := range p.onExit[from] {
+		hook(from, to)
+	}
+	p.state = to
+	p.recordEvent(Event{Type: EventTransition, From: from.String(), To: to.String()})
+	for _, hook := range p.onEnter[to] {
+		hook(from, to)
+	}
+
+	return nil
+}
+
+// MermaidDiagram renders the Transitions table as a mermaid state diagram.
+func MermaidDiagram() string {
+	var sb strings.Builder
+	sb.WriteString("stateDiagram-v2\n")
+	fmt.Fprintf(&sb, "    [*] --> %s : Program started\n", StateInit)
+	for _, t := range Transitions {
+		fmt.Fprintf(&sb, "    %s --> %s : %s\n", t.From, t.To, t.Label)
+	}
+	return sb.String()
+}
@@ -0,0 +1,93 @@
+package game
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadmeStateDiagram(t *testing.T) {
+	readme, err := os.ReadFile("../../README.md")
+	if err != nil {
+		t.Fatalf("Failed to read README: %v", err)
+	}
+
+	if !strings.Contains(string(readme), "```mermaid\n"+MermaidDiagram()+"```") {
+		t.Errorf("State diagram in README.md is outdated, run go generate ./...")
+	}
+}
+
+func TestStateTransitions(t *testing.T) {
+
+	mockBot := mockTelegramBot{
+		mSend: func(_ tgbotapi.Chattable) (tgbotapi.Message, error) {
+			return tgbotapi.Message{}, nil
+		},
+		receivedMessages: []string{},
+	}
+
+	santana := &tgbotapi.User{ID: 666, UserName: "Santana"}
+	jesus := &tgbotapi.User{ID: 123, UserName: "Jesus"}
+
+	hooks := []string{}
+	p := New(&mockBot, 1,
+		WithOutputDirectory(nil),
+		withoutShuffle(),
+		WithOnEnter(StateIntroduceSong, func(from State, to State) {
+			hooks = append(hooks, "enter "+from.String()+"->"+to.String())
+		}),
+		WithOnExit(StateWaitForReviews, func(from State, to State) {
+			hooks = append(hooks, "exit "+from.String()+"->"+to.String())
+		}),
+	)
+
+	tests := []struct {
+		update tgbotapi.Message
+		want   State
+	}{
+		{update: testMessage(jesus, "levyraati liity"), want: StateInit},
+		{update: testMessage(santana, "levyraati aloita"), want: StateWaitPanelistsToJoin},
+		{update: testMessage(jesus, "levyraati liity"), want: StateWaitPanelistsToJoin},
+		{update: testMessage(jesus, "levyraati jatka"), want: StateWaitPanelistsToJoin},
+		{update: testMessage(santana, "levyraati jatka"), want: StateAddSong},
+		{update: testMessage(santana, "levyraati esitä Song1 https://example.com/1"), want: StateAddSong},
+		{update: testMessage(jesus, "levyraati esitä Song2 https://example.com/2"), want: StateWaitForReviews},
+		{update: testMessage(santana, "levyraati ohita"), want: StateWaitForReviews},
+		{update: testMessage(jesus, "levyraati arvioi Good 8/10"), want: StateWaitForReviews},
+		{update: testMessage(santana, "levyraati lopeta"), want: StateInit},
+		{update: testMessage(santana, "levyraati lopeta"), want: StateInit},
+	}
+	for i, tt := range tests {
+		msg, err := ParseToMessage(tgbotapi.Update{Message: &tt.update})
+		if err != nil {
+			t.Fatalf("Failed to parse %d %q: %#v", i, tt.update.Text, err)
+		}
+		p.Handle(msg)
+		if p.State() != tt.want {
+			t.Fatalf("Message %d %q: state = %s, want %s", i, tt.update.Text, p.State(), tt.want)
+		}
+	}
+
+	expected := []string{
+		"enter ShuffleHost->IntroduceSong",
+		"exit WaitForReviews->IntroduceSong",
+		"enter WaitForReviews->IntroduceSong",
+		"exit WaitForReviews->Init",
+	}
+	if diff := cmp.Diff(expected, hooks); diff != "" {
+		t.Errorf("Hooks called in unexpected order:\n%s", diff)
+	}
+}
+
+func TestInvalidTransition(t *testing.T) {
+	p := New(&mockTelegramBot{}, 1, WithOutputDirectory(nil))
+	if err := p.transition(StateRevealReviews); err == nil {
+		t.Errorf("Transition from %s to %s should not be allowed", StateInit, StateRevealReviews)
+	}
+	if p.State() != StateInit {
+		t.Errorf("State changed to %s after an invalid transition", p.State())
+	}
+}
@@ -0,0 +1,192 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	"weezel/jukeboxjury/internal/logger"
+)
+
+// describeState returns a human readable description of the state.
+func (p *Play) describeState(state State) richText {
+	switch state {
+	case StateInit:
+		return p.tr(msgStateNotRunning)
+	case StateWaitPanelistsToJoin:
+		return p.tr(msgStateJoining)
+	case StateAddSong:
+		return p.tr(msgStateAddingSongs)
+	case StateWaitForCommentary:
+		return p.tr(msgStateCommenting)
+	case StateWaitForReviews:
+		return p.tr(msgStateReviewing)
+	case StateWaitForVotes:
+		return p.tr(msgStateVoting)
+	case StateStartGame, StateShuffleHost, StateIntroduceSong, StateRevealReviews, StateStopGame,
+		StateStartTournament, StateIntroduceMatch, StateDecideMatch:
+	}
+	return p.tr(msgStateOther, state)
+}
+
+type commandHelp struct {
+	command     string
+	usage       messageID // Arguments of the command, if any
+	description messageID
+}
+
+var (
+	helpStart    = commandHelp{command: CommandStart, description: msgHelpStart}
+	helpJoin     = commandHelp{command: CommandJoin, description: msgHelpJoin}
+	helpTeamJoin = commandHelp{command: CommandJoin, usage: msgUsageJoinTeam, description: msgHelpJoinTeam}
+	helpLateJoin = commandHelp{command: CommandJoin, description: msgHelpLateJoin}
+	helpContinue = commandHelp{command: CommandContinue, description: msgHelpContinue}
+	helpPresent  = commandHelp{command: CommandPresent, usage: msgUsagePresent, description: msgHelpPresent}
+	helpReview   = commandHelp{command: CommandReview, usage: msgUsageReview, description: msgHelpReview}
+	helpKick     = commandHelp{command: CommandKick, usage: msgUsageKick, description: msgHelpKick}
+	helpSkip     = commandHelp{command: CommandSkip, description: msgHelpSkip}
+	helpComment  = commandHelp{command: CommandComment, usage: msgUsageComment, description: msgHelpComment}
+	helpPass     = commandHelp{command: CommandSkip, description: msgHelpSkipComment}
+	helpStop     = commandHelp{command: CommandStop, description: msgHelpStop}
+	helpHelp     = commandHelp{command: CommandHelp, description: msgHelpHelp}
+	helpStatus   = commandHelp{command: CommandStatus, description: msgHelpStatus}
+	helpTour     = commandHelp{command: CommandTour, description: msgHelpTournament}
+	helpVote     = commandHelp{command: CommandVote, usage: msgUsageVote, description: msgHelpVote}
+	helpClose    = commandHelp{command: CommandContinue, description: msgHelpCloseMatch}
+)
+
+// availableCommands lists the commands which are valid in the current state.
+func (p *Play) availableCommands() []commandHelp {
+	var cmds []commandHelp
+	switch p.state {
+	case StateInit:
+		cmds = []commandHelp{helpStart}
+		if p.tournaments {
+			cmds = append(cmds, helpTour)
+		}
+	case StateWaitPanelistsToJoin:
+		cmds = []commandHelp{helpJoin, helpContinue, helpKick, helpStop}
+		if p.teams {
+			cmds[0] = helpTeamJoin
+		}
+	case StateAddSong:
+		cmds = []commandHelp{helpPresent, helpKick, helpStop}
+	case StateWaitForCommentary:
+		cmds = []commandHelp{helpComment, helpPass, helpKick, helpStop}
+	case StateWaitForReviews:
+		cmds = []commandHelp{helpReview, helpSkip, helpKick, helpStop}
+	case StateWaitForVotes:
+		cmds = []commandHelp{helpVote, helpClose, helpStop}
+	case StateStartGame, StateShuffleHost, StateIntroduceSong, StateRevealReviews, StateStopGame,
+		StateStartTournament, StateIntroduceMatch, StateDecideMatch:
+	}
+	lateJoin := p.state == StateAddSong || p.state == StateWaitForCommentary || p.state == StateWaitForReviews
+	if p.audienceMode != AudienceDisabled && lateJoin {
+		cmds = append(cmds, helpLateJoin)
+	}
+
+	return append(cmds, helpHelp, helpStatus)
+}
+
+// Help tells the sender which commands are valid in the current state.
+func (p *Play) Help(msg Message) {
+	logger.Logger.Debug().Stringer("state", p.state).Msgf("Panelist %s asked for help", msg.PlayerName)
+
+	var sb strings.Builder
+	sb.WriteString(string(p.tr(msgAvailableCommands, p.describeState(p.state))))
+	for _, cmd := range p.availableCommands() {
+		usage := p.prefix + " " + p.commandName(cmd.command)
+		switch {
+		case cmd.command == CommandReview:
+			usage += " " + p.tr(cmd.usage, p.ratingMax).plain()
+		case cmd.usage != "":
+			usage += " " + p.tr(cmd.usage).plain()
+		}
+		fmt.Fprintf(&sb, "\n%s - %s", code(usage), p.tr(cmd.description))
+	}
+
+	p.sendMessageToPanelist(msg.ChatID, richText(sb.String()))
+}
+
+// Status reports the progress of the game to the chat where it was asked.
+func (p *Play) Status(msg Message) {
+	logger.Logger.Debug().Stringer("state", p.state).Msgf("Panelist %s asked for status", msg.PlayerName)
+
+	if p.state == StateInit {
+		p.sendMessageToPanelist(msg.ChatID,
+			p.tr(msgNoGameRunning, p.prefix, p.commandName(CommandStart)),
+		)
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(string(p.describeState(p.state)))
+	if p.bracket != nil {
+		p.tournamentStatus(&sb)
+		p.sendMessageToPanelist(msg.ChatID, richText(sb.String()))
+		return
+	}
+
+	names := make([]string, 0, len(p.Panelists))
+	submitted, missingSongs, missingReviews, eliminated := []string{}, []string{}, []string{}, []string{}
+	for _, panelist := range p.Panelists {
+		if panelist.Team != "" {
+			names = append(names, panelist.Name+" ("+panelist.Team+")")
+		} else {
+			names = append(names, panelist.Name)
+		}
+		switch {
+		case panelist.EliminatedIn > 0:
+			eliminated = append(eliminated, panelist.Name)
+		case panelist.SongSubmitted:
+			submitted = append(submitted, panelist.Name)
+		default:
+			missingSongs = append(missingSongs, panelist.Name)
+		}
+		if !panelist.ReviewGiven {
+			missingReviews = append(missingReviews, panelist.Name)
+		}
+	}
+	sb.WriteString("\n" + string(p.tr(msgStatusPanelists, p.joinOrNone(names))))
+	if len(eliminated) > 0 {
+		sb.WriteString("\n" + string(p.tr(msgStatusEliminated, p.joinOrNone(eliminated))))
+	}
+
+	switch p.state {
+	case StateAddSong:
+		sb.WriteString("\n" + string(p.tr(msgStatusSubmitted, p.joinOrNone(submitted))))
+		sb.WriteString("\n" + string(p.tr(msgStatusMissingSongs, p.joinOrNone(missingSongs))))
+	case StateWaitForCommentary, StateWaitForReviews:
+		if p.host != nil {
+			sb.WriteString("\n" + string(p.tr(msgStatusCurrentSong, p.host.Name)))
+		}
+		if p.state == StateWaitForReviews {
+			sb.WriteString("\n" + string(p.tr(msgStatusMissingRevs, p.joinOrNone(missingReviews))))
+		}
+	case StateInit, StateStartGame, StateWaitPanelistsToJoin, StateShuffleHost,
+		StateIntroduceSong, StateRevealReviews, StateStopGame,
+		StateStartTournament, StateIntroduceMatch, StateWaitForVotes, StateDecideMatch:
+	}
+	if len(p.AudienceJurors) > 0 {
+		sb.WriteString("\n" + string(p.tr(msgStatusJurors, len(p.AudienceJurors))))
+	}
+	sb.WriteString("\n" + string(p.tr(msgStatusRunningFor, humanDuration(p.now().Sub(p.StartedAt)))))
+
+	p.sendMessageToPanelist(msg.ChatID, richText(sb.String()))
+}
+
+// tournamentStatus reports the match of the tournament night.
+func (p *Play) tournamentStatus(sb *strings.Builder) {
+	if p.match != nil && !p.match.Decided() {
+		home, away := p.bracket.Entry(p.match.Seeds[0]), p.bracket.Entry(p.match.Seeds[1])
+		sb.WriteString("\n" + string(p.tr(msgStatusMatch, entryLink(home), entryLink(away))))
+		sb.WriteString("\n" + string(p.tr(msgStatusVotes, len(p.votes))))
+	}
+	sb.WriteString("\n" + string(p.tr(msgStatusRunningFor, humanDuration(p.now().Sub(p.StartedAt)))))
+}
+
+func (p *Play) joinOrNone(names []string) richText {
+	if len(names) == 0 {
+		return p.tr(msgNone)
+	}
+	return escape(strings.Join(names, ", "))
+}
@@ -0,0 +1,50 @@
+package game
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestHelpAndStatus(t *testing.T) {
+	mockBot := mockTelegramBot{
+		mSend: func(_ tgbotapi.Chattable) (tgbotapi.Message, error) {
+			return tgbotapi.Message{}, nil
+		},
+		receivedMessages: []string{},
+	}
+
+	p := New(&mockBot, 1, WithOutputDirectory(nil))
+	jesus := NewPanelist("Jesus", 123)
+	jesus.SongSubmitted = true
+	p.Panelists = []*Panelist{NewPanelist("Santana", 666), jesus}
+	p.StartedAt = time.Now().Add(-time.Minute * 5)
+	p.state = StateAddSong
+
+	p.Help(Message{ChatID: 123})
+	help := mockBot.receivedMessages[len(mockBot.receivedMessages)-1]
+	for _, expected := range []string{
+		"Game is waiting for songs",
+		"<code>levyraati esitä &lt;description&gt; &lt;link&gt;</code>",
+		"<code>levyraati tila</code> - show the game status",
+	} {
+		if !strings.Contains(help, expected) {
+			t.Errorf("Help %q doesn't contain %q", help, expected)
+		}
+	}
+	if strings.Contains(help, "review") {
+		t.Errorf("Help %q lists review command while adding songs", help)
+	}
+
+	p.Status(Message{ChatID: 123})
+	expected := "Game is waiting for songs\n" +
+		"Panelists: Santana, Jesus\n" +
+		"Songs submitted: Jesus\n" +
+		"Songs missing: Santana\n" +
+		"Game has been running for 5 min"
+	if status := mockBot.receivedMessages[len(mockBot.receivedMessages)-1]; status != expected {
+		t.Errorf("Status = %q, want %q", status, expected)
+	}
+}
@@ -0,0 +1,139 @@
+package game
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"weezel/jukeboxjury/internal/logger"
+)
+
+// WithTeams lets the panelists join a team with the join command, e.g.
+// levyraati liity <team>. Team members don't review each other's songs,
+// and the teams are ranked by the average score of their songs. Panelists
+// who join without a team play on their own.
+func WithTeams(enabled bool) PlayOption {
+	return func(p *Play) {
+		p.teams = enabled
+	}
+}
+
+// TeamScore is the result of a team at the end of the game.
+type TeamScore struct {
+	Name    string
+	Members []string // Panelists whose song was revealed
+	Score   float64  // Average score of the members' songs
+}
+
+// teamName returns the team named in the text. Teams are matched case
+// insensitively, keeping the name as the team was first declared.
+func (p *Play) teamName(text string) string {
+	name := strings.TrimSpace(text)
+	for _, panelist := range p.Panelists {
+		if strings.EqualFold(panelist.Team, name) {
+			return panelist.Team
+		}
+	}
+	return name
+}
+
+// announceJoined tells about the panelist who joined, and their team.
+func (p *Play) announceJoined(panelist *Panelist) {
+	if panelist.Team == "" {
+		p.announceProgress(p.tr(msgJoined, bold(panelist.Name)))
+		return
+	}
+	p.announceProgress(p.tr(msgJoinedTeam, bold(panelist.Name), bold(panelist.Team)))
+}
+
+// switchTeam moves the panelist who has already joined into the team
+// named in the message. Returns false when the message names no team.
+func (p *Play) switchTeam(msg Message) bool {
+	i := slices.IndexFunc(p.Panelists, func(pan *Panelist) bool { return pan.uid == msg.FromID })
+	if !p.teams || msg.Text == "" || i == -1 {
+		return false
+	}
+
+	panelist := p.Panelists[i]
+	panelist.Team = p.teamName(msg.Text)
+	logger.Logger.Info().
+		Str("team", panelist.Team).
+		Msgf("Panelist %s with ID %d switched team", msg.PlayerName, msg.FromID)
+	p.announceProgress(p.tr(msgTeamChanged, bold(panelist.Name), bold(panelist.Team)))
+	return true
+}
+
+// teammates tells whether the panelists play in the same team.
+func teammates(a *Panelist, b *Panelist) bool {
+	return a.Team != "" && a.Team == b.Team
+}
+
+// excludeTeammates marks the host's team members as having reviewed the
+// song, since they can't review it.
+func (p *Play) excludeTeammates(host *Panelist) {
+	for _, panelist := range p.Panelists {
+		if teammates(panelist, host) {
+			panelist.ReviewGiven = true
+		}
+	}
+}
+
+// singleTeam tells whether every panelist is in the same team, in which
+// case nobody could review the songs.
+func (p *Play) singleTeam() bool {
+	if !p.teams || len(p.Panelists) < 2 {
+		return false
+	}
+	for _, panelist := range p.Panelists[1:] {
+		if !teammates(panelist, p.Panelists[0]) {
+			return false
+		}
+	}
+	return true
+}
+
+// TeamScores ranks the teams by the average score of their revealed
+// songs, the highest first.
+func (p Play) TeamScores() []TeamScore {
+	if !p.teams {
+		return nil
+	}
+
+	scores := []TeamScore{}
+	for _, panelist := range p.Panelists {
+		if panelist.Team == "" || panelist.Song.RevealedAt.IsZero() {
+			continue
+		}
+		i := slices.IndexFunc(scores, func(score TeamScore) bool { return score.Name == panelist.Team })
+		if i == -1 {
+			i = len(scores)
+			scores = append(scores, TeamScore{Name: panelist.Team})
+		}
+		scores[i].Members = append(scores[i].Members, panelist.Name)
+		scores[i].Score += panelist.Song.AverageScore
+	}
+	for i := range scores {
+		scores[i].Score /= float64(len(scores[i].Members))
+	}
+	slices.SortStableFunc(scores, func(a, b TeamScore) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	return scores
+}
+
+// announceTeamScores tells the channel how the teams did.
+func (p *Play) announceTeamScores() {
+	scores := p.TeamScores()
+	if len(scores) == 0 {
+		return
+	}
+
+	var sb strings.Builder
+	sb.WriteString(string(p.tr(msgTeamScores)))
+	for i, score := range scores {
+		fmt.Fprintf(&sb, "\n%d. %s", i+1,
+			p.tr(msgTeamRank, bold(score.Name), score.Score, strings.Join(score.Members, ", ")))
+	}
+	p.sendMessageToChannel(richText(sb.String()))
+}
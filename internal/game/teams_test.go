@@ -0,0 +1,102 @@
+package game
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestTeams(t *testing.T) {
+	resultsDir := t.TempDir()
+	p, bot := newScriptedGame(t, WithOutputDirectory(&resultsDir), WithTeams(true))
+
+	santana := &tgbotapi.User{ID: 666, UserName: "Santana"}
+	jesus := &tgbotapi.User{ID: 123, UserName: "Jesus"}
+	pjotr := &tgbotapi.User{ID: 7, UserName: "Pjotr"}
+	maria := &tgbotapi.User{ID: 42, UserName: "Maria"}
+	runScript(t, p, bot, []scriptStep{
+		{
+			update: testMessage(santana, "levyraati aloita Red"),
+			want:   StateWaitPanelistsToJoin,
+			response: "User <b>Santana</b> started a new team game, join your team by using command: " +
+				"levyraati liity &lt;team&gt;",
+		},
+		{
+			update:   testMessage(jesus, "levyraati liity red"),
+			want:     StateWaitPanelistsToJoin,
+			response: "User <b>Jesus</b> joined the game in team <b>Red</b>",
+		},
+		{
+			update:   testMessage(santana, "levyraati jatka"),
+			want:     StateWaitPanelistsToJoin,
+			response: "Every panelist is in the same team, so nobody could review the songs",
+		},
+		{update: testMessage(pjotr, "levyraati liity Blue"), want: StateWaitPanelistsToJoin},
+		{
+			update:   testMessage(maria, "levyraati liity"),
+			want:     StateWaitPanelistsToJoin,
+			response: "User <b>Maria</b> joined the game",
+		},
+		{
+			update:   testMessage(maria, "levyraati liity BLUE"),
+			want:     StateWaitPanelistsToJoin,
+			response: "<b>Maria</b> switched to team <b>Blue</b>",
+		},
+		{update: testMessage(santana, "levyraati jatka"), want: StateAddSong},
+		{update: testMessage(santana, "levyraati esitä Song1 https://example.com/1"), want: StateAddSong},
+		{update: testMessage(jesus, "levyraati esitä Song2 https://example.com/2"), want: StateAddSong},
+		{update: testMessage(pjotr, "levyraati esitä Song3 https://example.com/3"), want: StateAddSong},
+		{update: testMessage(maria, "levyraati esitä Song4 https://example.com/4"), want: StateWaitForReviews},
+		// Santana's song
+		{
+			update:   testMessage(jesus, "levyraati arvioi Great 10/10"),
+			want:     StateWaitForReviews,
+			response: "You can't review the songs of your own team",
+		},
+		{update: testMessage(pjotr, "levyraati arvioi Good 8/10"), want: StateWaitForReviews},
+		{update: testMessage(maria, "levyraati arvioi Fine 6/10"), want: StateWaitForReviews},
+		// Jesus' song
+		{update: testMessage(pjotr, "levyraati arvioi Weak 4/10"), want: StateWaitForReviews},
+		{update: testMessage(maria, "levyraati arvioi Fine 6/10"), want: StateWaitForReviews},
+		// Pjotr's song
+		{
+			update:   testMessage(maria, "levyraati arvioi Great 10/10"),
+			want:     StateWaitForReviews,
+			response: "You can't review the songs of your own team",
+		},
+		{update: testMessage(santana, "levyraati arvioi Great 9/10"), want: StateWaitForReviews},
+		{update: testMessage(jesus, "levyraati arvioi Good 7/10"), want: StateWaitForReviews},
+		// Maria's song
+		{update: testMessage(santana, "levyraati arvioi Average 5/10"), want: StateWaitForReviews},
+		{update: testMessage(jesus, "levyraati arvioi Average 5/10"), want: StateInit},
+	})
+
+	// The team scores follow the winner, before the game is cleared
+	sent := bot.receivedMessages
+	expected := "Team scores by the average score of their songs:\n" +
+		"1. <b>Blue</b> 6.50 (Pjotr, Maria)\n2. <b>Red</b> 6.00 (Santana, Jesus)"
+	if scores := sent[len(sent)-2]; scores != expected {
+		t.Errorf("Team scores = %q, want %q", scores, expected)
+	}
+
+	results, err := filepath.Glob(filepath.Join(resultsDir, "jukebox_jury_results_*.html"))
+	if err != nil || len(results) != 1 {
+		t.Fatalf("Expected a single results file, got %v: %v", results, err)
+	}
+	html, err := os.ReadFile(results[0])
+	if err != nil {
+		t.Fatalf("Failed to read results: %v", err)
+	}
+	for _, expected := range []string{
+		"<h2>Santana</h2>\n        <p class=\"team\">Team: Red</p>",
+		"<li>Blue: 6.50 (Pjotr, Maria)</li>",
+		"<li>Red: 6.00 (Santana, Jesus)</li>",
+	} {
+		if !strings.Contains(string(html), expected) {
+			t.Errorf("Results don't contain %q", expected)
+		}
+	}
+}
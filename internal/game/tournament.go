@@ -0,0 +1,270 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"weezel/jukeboxjury/internal/logger"
+	"weezel/jukeboxjury/internal/tournament"
+)
+
+const (
+	// bracketFile keeps the bracket of the tournament between the game nights.
+	bracketFile = "jukebox_jury_tournament.json"
+	// bracketPage shows the bracket next to the results.
+	bracketPage = "jukebox_jury_tournament.html"
+)
+
+// WithTournament lets the chat run a knockout tournament between the songs
+// of the previous games. The songs are seeded from the JSON results in the
+// results directory, where the bracket is saved between the game nights.
+func WithTournament(enabled bool) PlayOption {
+	return func(p *Play) {
+		p.tournaments = enabled
+	}
+}
+
+// withBracketLoader overrides how the bracket of a tournament night is loaded.
+func withBracketLoader(load func() (*tournament.Bracket, error)) PlayOption {
+	return func(p *Play) {
+		p.loadBracket = load
+	}
+}
+
+// seasonBracket loads the saved bracket. Once the saved tournament has a
+// champion, a new one is drawn from the songs played after it began and
+// the old bracket is archived once the new one is saved.
+func (p *Play) seasonBracket() (*tournament.Bracket, error) {
+	if p.resultsDirectory == nil {
+		return nil, errors.New("tournament needs a results directory")
+	}
+
+	fpath := filepath.Join(*p.resultsDirectory, bracketFile)
+	var saved tournament.Bracket
+	content, err := os.ReadFile(fpath)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+	case err != nil:
+		return nil, fmt.Errorf("file %q read: %w", fpath, err)
+	default:
+		if err = json.Unmarshal(content, &saved); err != nil {
+			return nil, fmt.Errorf("file %q parse: %w", fpath, err)
+		}
+		if err = saved.Validate(); err != nil {
+			return nil, fmt.Errorf("file %q: %w", fpath, err)
+		}
+		if saved.Champion() == nil {
+			return &saved, nil
+		}
+	}
+
+	entries, err := LoadSeason(*p.resultsDirectory)
+	if err != nil {
+		return nil, err
+	}
+	season := []tournament.Entry{}
+	for _, entry := range entries {
+		if entry.PlayedAt.After(saved.CreatedAt) {
+			season = append(season, entry)
+		}
+	}
+	bracket, err := tournament.New(season, p.now())
+	if err != nil {
+		return nil, err
+	}
+	if err = p.writeBracket(bracket, ""); err != nil {
+		return nil, err
+	}
+	if !saved.CreatedAt.IsZero() {
+		p.archiveBracket(&saved)
+	}
+
+	return bracket, nil
+}
+
+// archiveBracket saves the finished tournament under the names of its
+// files suffixed with the time it began.
+func (p *Play) archiveBracket(finished *tournament.Bracket) {
+	suffix := "_" + finished.CreatedAt.Local().Format("2006-01-02T150405")
+	if err := p.writeBracket(finished, suffix); err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to archive the tournament")
+	}
+}
+
+// writeBracket saves the bracket as JSON and renders its page, the suffix
+// added to the names of the files.
+func (p *Play) writeBracket(bracket *tournament.Bracket, suffix string) error {
+	fname := func(name string) string {
+		ext := filepath.Ext(name)
+		return strings.TrimSuffix(name, ext) + suffix + ext
+	}
+	err := p.writeResultsFile(fname(bracketFile), func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(bracket)
+	})
+	if err != nil {
+		return err
+	}
+	return p.writeResultsFile(fname(bracketPage), bracket.Render)
+}
+
+// saveBracket saves the bracket after a decided match, so that a stopped
+// tournament night can be continued.
+func (p *Play) saveBracket() {
+	if p.resultsDirectory == nil {
+		return
+	}
+
+	if err := p.writeBracket(p.bracket, ""); err != nil {
+		logger.Logger.Error().Err(err).Msg("Failed to save the tournament")
+		p.sendMessageToChannel(p.tr(msgSaveFailed))
+	}
+}
+
+// entryLink renders the song of the tournament as a link.
+func entryLink(entry *tournament.Entry) richText {
+	return songLink(&Song{Description: entry.Description, URL: entry.URL})
+}
+
+// voteCommand returns the command which votes for the song in the slot.
+func (p *Play) voteCommand(slot int) string {
+	return fmt.Sprintf("%s %s %d", p.prefix, p.commandName(CommandVote), slot+1)
+}
+
+func (p *Play) startTournament(msg Message) State {
+	logger.Logger.Debug().Msg("State: Tournament night is starting")
+
+	p.StartedAt = p.now().Local()
+	p.gameStarterUID = msg.FromID
+	bracket, err := p.loadBracket()
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Couldn't start the tournament")
+		if errors.Is(err, tournament.ErrTooFewSongs) {
+			p.sendMessageToChannel(p.tr(msgTooFewSongs))
+		} else {
+			p.sendMessageToChannel(p.tr(msgNoTournament))
+		}
+		return StateInit
+	}
+	p.recordEvent(Event{Type: EventBracket, Bracket: bracket})
+	p.bracket = bracket
+
+	logger.Logger.Info().
+		Str("tournament_starter_name", msg.PlayerName).
+		Int64("tournament_starter_id", msg.FromID).
+		Int("songs", len(bracket.Entries)).
+		Msg("Tournament night started")
+	p.sendMessageToChannel(p.tr(msgTournamentStarted, bold(msg.PlayerName), len(bracket.Entries)))
+
+	return StateIntroduceMatch
+}
+
+func (p *Play) introduceMatch(_ Message) State {
+	logger.Logger.Debug().Msg("State: Introduce the next match")
+
+	match, round := p.bracket.Next()
+	if match == nil {
+		champion := p.bracket.Champion()
+		p.sendMessageToChannel(p.tr(msgChampion, entryLink(champion), bold(champion.Panelist)))
+		p.announceBracket()
+		return StateInit
+	}
+	// The rounds are played on separate game nights
+	if p.match != nil && round != p.matchRound {
+		p.sendMessageToChannel(p.tr(msgRoundFinished, p.matchRound+1))
+		p.announceBracket()
+		return StateInit
+	}
+
+	p.match, p.matchRound = match, round
+	p.votes = map[int64]int{}
+	home, away := p.bracket.Entry(match.Seeds[0]), p.bracket.Entry(match.Seeds[1])
+	p.sendMessageToChannel(p.tr(msgMatch, round+1,
+		entryLink(home), bold(home.Panelist),
+		entryLink(away), bold(away.Panelist),
+		p.voteCommand(0), p.voteCommand(1),
+		p.prefix+" "+p.commandName(CommandContinue),
+	))
+
+	return StateWaitForVotes
+}
+
+func (p *Play) waitForVotes(msg Message) State {
+	logger.Logger.Debug().Msg("State: Wait for votes")
+
+	switch msg.Command {
+	case CommandVote:
+		p.vote(msg)
+		return StateWaitForVotes
+	case CommandContinue:
+		if !p.Authorize(msg, ActionContinue) {
+			return StateWaitForVotes
+		}
+		return StateDecideMatch
+	}
+
+	p.sendMessageToPanelist(msg.ChatID, p.tr(msgHowToVote, p.voteCommand(0), p.voteCommand(1)))
+	return StateWaitForVotes
+}
+
+// vote records the vote of the sender. Votes can be changed until the
+// match is closed, but not in the match of the voter's own song.
+func (p *Play) vote(msg Message) {
+	choice, err := strconv.Atoi(strings.TrimSpace(msg.Text))
+	if err != nil || choice < 1 || choice > 2 {
+		p.sendMessageToPanelist(msg.ChatID, p.tr(msgHowToVote, p.voteCommand(0), p.voteCommand(1)))
+		return
+	}
+	for _, seed := range p.match.Seeds {
+		if p.bracket.Entry(seed).PanelistID == msg.FromID {
+			p.sendMessageToPanelist(msg.ChatID, p.tr(msgOwnMatch))
+			return
+		}
+	}
+
+	_, changed := p.votes[msg.FromID]
+	p.votes[msg.FromID] = choice - 1
+	logger.Logger.Info().Msgf("Panelist %s with ID %d voted", msg.PlayerName, msg.FromID)
+	if changed {
+		p.sendMessageToPanelist(msg.ChatID, p.tr(msgVoteChanged))
+		return
+	}
+	p.sendMessageToChannel(p.tr(msgVoted, bold(msg.PlayerName)))
+}
+
+func (p *Play) decideMatch(_ Message) State {
+	logger.Logger.Debug().Msg("State: Decide the match")
+
+	var votes [2]int
+	for _, slot := range p.votes {
+		votes[slot]++
+	}
+	winner, err := p.bracket.Decide(p.match, votes)
+	if err != nil {
+		logger.Logger.Error().Err(err).Msg("Couldn't decide the match")
+		return StateIntroduceMatch
+	}
+	logger.Logger.Info().
+		Ints("votes", votes[:]).
+		Msgf("Song %s of %s won the match", winner.URL, winner.Panelist)
+	p.sendMessageToChannel(p.tr(msgMatchWinner, entryLink(winner), bold(winner.Panelist), votes[0], votes[1]))
+	p.saveBracket()
+
+	return StateIntroduceMatch
+}
+
+// announceBracket links the bracket at the end of the tournament night.
+func (p *Play) announceBracket() {
+	if p.resultsDirectory == nil {
+		return
+	}
+	p.sendMessageToChannel(p.tr(msgBracket, p.resultsURL.JoinPath(bracketPage).String()))
+}
@@ -0,0 +1,201 @@
+package game
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"weezel/jukeboxjury/internal/integration/telegram"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/google/go-cmp/cmp"
+)
+
+// playedSong is a song of a previous game, unrevealed when revealed is zero.
+func playedSong(panelist string, uid int64, song string, score float64, revealed time.Time) songResult {
+	return songResult{
+		Panelist:   panelist,
+		PanelistID: uid,
+		Song: &Song{
+			Description:  "Song" + song,
+			URL:          "https://example.com/" + song,
+			AverageScore: score,
+			RevealedAt:   revealed,
+		},
+	}
+}
+
+func writeSongResults(t *testing.T, fpath string, songs ...songResult) {
+	t.Helper()
+	results, err := json.Marshal(songResults{Songs: songs})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(fpath, results, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTournament(t *testing.T) {
+	resultsDir := t.TempDir()
+	logDir := t.TempDir()
+
+	// A previous game with three revealed songs
+	played := time.Date(2024, 10, 18, 20, 0, 0, 0, time.UTC)
+	writeSongResults(t, filepath.Join(resultsDir, "jukebox_jury_results_2024-10-18T200000.json"),
+		playedSong("Pjotr", 7, "3", 4, played),
+		playedSong("Santana", 666, "1", 8, played),
+		playedSong("Jesus", 123, "2", 6, played),
+		playedSong("Maria", 42, "4", 0, time.Time{}),
+	)
+
+	mockBot := mockTelegramBot{
+		mSend: func(_ tgbotapi.Chattable) (tgbotapi.Message, error) {
+			return tgbotapi.Message{}, nil
+		},
+		receivedMessages: []string{},
+	}
+	now := time.Date(2024, 11, 1, 20, 0, 0, 0, time.UTC)
+	resultsURL, _ := url.Parse("https://example.com/jj")
+	p := New(&mockBot, 1,
+		WithOutputDirectory(&resultsDir),
+		WithResultsURL(resultsURL),
+		WithEventLog(logDir),
+		WithClock(func() time.Time { return now }),
+		WithTournament(true),
+	)
+
+	santana := &tgbotapi.User{ID: 666, UserName: "Santana"}
+	jesus := &tgbotapi.User{ID: 123, UserName: "Jesus"}
+	pjotr := &tgbotapi.User{ID: 7, UserName: "Pjotr"}
+	maria := &tgbotapi.User{ID: 42, UserName: "Maria"}
+	night := func(updates []tgbotapi.Message, want []string) {
+		t.Helper()
+		sent := len(mockBot.receivedMessages)
+		for _, update := range updates {
+			msg, err := ParseToMessage(tgbotapi.Update{Message: &update})
+			if err != nil {
+				t.Fatalf("Failed to parse %q: %#v", update.Text, err)
+			}
+			p.Handle(msg)
+		}
+		if diff := cmp.Diff(want, mockBot.receivedMessages[sent:]); diff != "" {
+			t.Errorf("Messages mismatch (-want +got):\n%s", diff)
+		}
+		if p.State() != StateInit {
+			t.Errorf("State = %s, want %s", p.State(), StateInit)
+		}
+	}
+
+	// The first seed has a bye, so the first night has a single match
+	night([]tgbotapi.Message{
+		testMessage(santana, "levyraati turnaus"),
+		testMessage(jesus, "levyraati äänestä 1"),
+		testMessage(maria, "levyraati äänestä 3"),
+		testMessage(maria, "levyraati äänestä 2"),
+		testMessage(santana, "levyraati äänestä 1"),
+		testMessage(santana, "levyraati äänestä 2"),
+		testMessage(maria, "levyraati jatka"),
+		testMessage(santana, "levyraati jatka"),
+	}, []string{
+		"<b>Santana</b> started a tournament night, 3 songs are competing",
+		"Tournament round 1:\n" +
+			"1. <a href=\"https://example.com/2\">Song2</a> from <b>Jesus</b>\n" +
+			"2. <a href=\"https://example.com/3\">Song3</a> from <b>Pjotr</b>\n" +
+			"Vote with levyraati äänestä 1 or levyraati äänestä 2, " +
+			"the match is closed with levyraati jatka",
+		"You can't vote in the match of your own song",
+		"Vote with levyraati äänestä 1 or levyraati äänestä 2",
+		"<b>Maria</b> voted",
+		"<b>Santana</b> voted",
+		"Your vote was changed",
+		"Sorry, only the game starter, bot admins or chat admins can continue the game",
+		"<a href=\"https://example.com/3\">Song3</a> from <b>Pjotr</b> advances with 0-2 votes",
+		"Round 1 of the tournament is finished, the tournament continues on the next game night",
+		"Tournament bracket: https://example.com/jj/jukebox_jury_tournament.html",
+		"Ending the game",
+	})
+	page, err := os.ReadFile(filepath.Join(resultsDir, bracketPage))
+	if err != nil || !strings.Contains(string(page), `<span class="votes">2</span>`) {
+		t.Errorf("Bracket page wasn't saved after the match: %v", err)
+	}
+
+	now = now.Add(7 * 24 * time.Hour)
+	night([]tgbotapi.Message{
+		testMessage(jesus, "levyraati turnaus"),
+		testMessage(jesus, "levyraati äänestä 1"),
+		testMessage(jesus, "levyraati jatka"),
+	}, []string{
+		"<b>Jesus</b> started a tournament night, 3 songs are competing",
+		"Tournament round 2:\n" +
+			"1. <a href=\"https://example.com/1\">Song1</a> from <b>Santana</b>\n" +
+			"2. <a href=\"https://example.com/3\">Song3</a> from <b>Pjotr</b>\n" +
+			"Vote with levyraati äänestä 1 or levyraati äänestä 2, " +
+			"the match is closed with levyraati jatka",
+		"<b>Jesus</b> voted",
+		"<a href=\"https://example.com/1\">Song1</a> from <b>Santana</b> advances with 1-0 votes",
+		"The tournament is over, the champion is " +
+			"<a href=\"https://example.com/1\">Song1</a> from <b>Santana</b>!",
+		"Tournament bracket: https://example.com/jj/jukebox_jury_tournament.html",
+		"Ending the game",
+	})
+
+	// The night is replayed from the logged bracket without the saved files
+	logs, err := filepath.Glob(filepath.Join(logDir, "jukebox_jury_events_*.jsonl"))
+	if err != nil || len(logs) != 2 {
+		t.Fatalf("Expected an event log for both nights, got %v: %v", logs, err)
+	}
+	fin, err := os.Open(logs[1])
+	if err != nil {
+		t.Fatalf("Failed to open event log: %v", err)
+	}
+	defer fin.Close()
+	events, err := ReadEvents(fin)
+	if err != nil {
+		t.Fatalf("Failed to read event log: %v", err)
+	}
+	var out bytes.Buffer
+	if _, err = Replay(events, telegram.NewFakeBot(&out), WithOutputDirectory(nil)); err != nil {
+		t.Fatalf("Replay diverged: %v", err)
+	}
+	if !strings.Contains(out.String(), "the champion is <a href=\"https://example.com/1\">Song1</a>") {
+		t.Errorf("Replay didn't decide the champion:\n%s", out.String())
+	}
+
+	// A new tournament needs new songs
+	now = now.Add(7 * 24 * time.Hour)
+	night([]tgbotapi.Message{testMessage(santana, "levyraati turnaus")}, []string{
+		"A tournament needs at least two songs from the previous games",
+		"Ending the game",
+	})
+	if _, err = os.Stat(filepath.Join(resultsDir, bracketFile)); err != nil {
+		t.Errorf("Finished tournament was archived without a new one: %v", err)
+	}
+
+	writeSongResults(t, filepath.Join(resultsDir, "jukebox_jury_results_2024-11-15T200000.json"),
+		playedSong("Pjotr", 7, "5", 7, now.Add(-time.Hour)),
+		playedSong("Maria", 42, "6", 9, now.Add(-time.Hour)),
+	)
+	night([]tgbotapi.Message{
+		testMessage(pjotr, "levyraati turnaus"),
+		testMessage(pjotr, "levyraati lopeta"),
+	}, []string{
+		"<b>Pjotr</b> started a tournament night, 2 songs are competing",
+		"Tournament round 1:\n" +
+			"1. <a href=\"https://example.com/6\">Song6</a> from <b>Maria</b>\n" +
+			"2. <a href=\"https://example.com/5\">Song5</a> from <b>Pjotr</b>\n" +
+			"Vote with levyraati äänestä 1 or levyraati äänestä 2, " +
+			"the match is closed with levyraati jatka",
+		"The game was stopped by <b>Pjotr</b>",
+		"Ending the game",
+	})
+	archived, err := filepath.Glob(filepath.Join(resultsDir, "jukebox_jury_tournament_*"))
+	if err != nil || len(archived) != 2 {
+		t.Errorf("Expected the finished tournament to be archived, got %v: %v", archived, err)
+	}
+}
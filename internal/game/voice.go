@@ -0,0 +1,162 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"weezel/jukeboxjury/internal/logger"
+)
+
+// DefaultTranscribeTimeout is how long a voice review may take to transcribe.
+const DefaultTranscribeTimeout = 2 * time.Minute
+
+// voiceDownloadTimeout limits downloading a voice note from Telegram.
+const voiceDownloadTimeout = 30 * time.Second
+
+// Transcriber turns a voice review into text. The audio is the path of
+// the voice note as downloaded from Telegram, usually an Ogg Opus file,
+// or empty when the voice note couldn't be saved.
+type Transcriber interface {
+	Transcribe(ctx context.Context, audio string) (string, error)
+}
+
+// TranscriberFunc adapts a function to a Transcriber.
+type TranscriberFunc func(ctx context.Context, audio string) (string, error)
+
+func (f TranscriberFunc) Transcribe(ctx context.Context, audio string) (string, error) {
+	return f(ctx, audio)
+}
+
+// WithTranscriber transcribes voice reviews into the review texts. Zero
+// timeout lets the transcription take as long as it takes.
+func WithTranscriber(transcriber Transcriber, timeout time.Duration) PlayOption {
+	return func(p *Play) {
+		p.transcriber = transcriber
+		p.transcribeTimeout = timeout
+	}
+}
+
+// startVoiceReview saves the voice note of the review next to the results
+// and transcribes it in the background, so that the game goes on meanwhile.
+// The review is added once the transcript is ready.
+func (p *Play) startVoiceReview(msg Message) {
+	p.voiceReviews[msg.FromID] = p.host.Song
+	p.background(func() func() {
+		audio, err := p.saveVoiceNote(msg)
+		if err != nil {
+			logger.Logger.Error().Err(err).Msgf("Couldn't save the voice review of %s", msg.PlayerName)
+		}
+		transcript := p.transcribe(audio)
+		if audio != "" {
+			audio = filepath.Base(audio)
+		}
+		return func() { p.finishVoiceReview(msg, transcript, audio) }
+	})
+}
+
+// finishVoiceReview adds the transcribed voice review, unless the game has
+// moved on from the song meanwhile. Transcripts are logged so that replays,
+// which have no voice notes, get the same review texts at the same points
+// of the game.
+func (p *Play) finishVoiceReview(msg Message, transcript string, audio string) {
+	p.recordEvent(Event{Type: EventTranscript, Message: &msg, Transcript: transcript})
+
+	song, ok := p.voiceReviews[msg.FromID]
+	delete(p.voiceReviews, msg.FromID)
+	reviewer := p.findReviewer(msg.FromID)
+	if !ok || p.state != StateWaitForReviews || p.host.Song != song || reviewer == nil {
+		logger.Logger.Warn().Msgf("Voice review of %s came after the song was over", msg.PlayerName)
+		return
+	}
+
+	// Without a transcript the space still separates the rating as
+	// AddReview expects
+	if !p.addReview(reviewer, msg, transcript+" "+msg.Text, audio) {
+		return
+	}
+	// The game continues from here unless the review was finished while
+	// handling the message, which happens when work runs immediately
+	if !p.running {
+		p.resume()
+	}
+}
+
+// saveVoiceNote downloads the voice note of the message into the results
+// directory. Returns the path of the saved file, or empty path when there
+// is no results directory.
+func (p *Play) saveVoiceNote(msg Message) (string, error) {
+	if p.resultsDirectory == nil {
+		return "", nil
+	}
+
+	// The URL contains the bot token, so it's kept out of the errors
+	fileURL, err := p.bot.GetFileDirectURL(msg.Voice)
+	if err != nil {
+		return "", fmt.Errorf("voice note URL: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), voiceDownloadTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		return "", errors.New("voice note request: invalid URL")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", errors.New("voice note download failed")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("voice note download: %s", resp.Status)
+	}
+
+	ext := path.Ext(req.URL.Path)
+	if ext == "" {
+		ext = ".oga"
+	}
+	fname := fmt.Sprintf("jukebox_jury_voice_%s_%d%s",
+		p.now().Local().Format("2006-01-02T150405"), msg.FromID, ext)
+	fpath := filepath.Join(*p.resultsDirectory, fname)
+	//nolint:gosec // Voice notes are served with the results
+	fout, err := os.OpenFile(fpath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return "", fmt.Errorf("file %q creation: %w", fpath, err)
+	}
+	if _, err = io.Copy(fout, resp.Body); err != nil {
+		fout.Close()
+		os.Remove(fpath)
+		return "", fmt.Errorf("file %q write: %w", fpath, err)
+	}
+	if err = fout.Close(); err != nil {
+		return "", fmt.Errorf("file %q close: %w", fpath, err)
+	}
+
+	return fpath, nil
+}
+
+// transcribe transcribes the saved voice note.
+func (p *Play) transcribe(audio string) string {
+	if p.transcriber == nil {
+		return ""
+	}
+
+	ctx := context.Background()
+	if p.transcribeTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.transcribeTimeout)
+		defer cancel()
+	}
+	transcript, err := p.transcriber.Transcribe(ctx, audio)
+	if err != nil {
+		logger.Logger.Error().Err(err).Str("audio", audio).Msg("Couldn't transcribe the voice review")
+		transcript = ""
+	}
+
+	return transcript
+}
@@ -0,0 +1,303 @@
+package game
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"weezel/jukeboxjury/internal/integration/telegram"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestVoiceReviews(t *testing.T) {
+	resultsDir := t.TempDir()
+	logDir := t.TempDir()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("OggS voice"))
+	}))
+	defer server.Close()
+
+	mockBot := mockTelegramBot{
+		mSend: func(_ tgbotapi.Chattable) (tgbotapi.Message, error) {
+			return tgbotapi.Message{}, nil
+		},
+		receivedMessages: []string{},
+		fileURLs: map[string]string{
+			"voice-1": server.URL + "/file/bot123:token/voice/file_1.oga",
+			"voice-2": server.URL + "/file/bot123:token/voice/file_2.oga",
+		},
+	}
+	// The first voice review is transcribed and the second one fails
+	transcribed := 0
+	transcriber := TranscriberFunc(func(_ context.Context, audio string) (string, error) {
+		transcribed++
+		if transcribed > 1 {
+			return "", errors.New("out of memory")
+		}
+		content, err := os.ReadFile(audio)
+		return "Transcript of " + string(content), err
+	})
+	now := time.Date(2024, 10, 18, 20, 0, 0, 0, time.UTC)
+	p := New(&mockBot, 1,
+		WithOutputDirectory(&resultsDir),
+		WithEventLog(logDir),
+		withoutShuffle(),
+		WithClock(func() time.Time { return now }),
+		WithTranscriber(transcriber, time.Second),
+	)
+	handle := func(from *tgbotapi.User, text string, voice string) {
+		t.Helper()
+		update := testMessage(from, text)
+		if voice != "" {
+			update.Text, update.Caption = "", text
+			update.Voice = &tgbotapi.Voice{FileID: voice}
+		}
+		msg, err := ParseToMessage(tgbotapi.Update{Message: &update})
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %#v", text, err)
+		}
+		p.Handle(msg)
+	}
+	lastMessage := func() string {
+		return mockBot.receivedMessages[len(mockBot.receivedMessages)-1]
+	}
+
+	santana := &tgbotapi.User{ID: 666, UserName: "Santana"}
+	jesus := &tgbotapi.User{ID: 123, UserName: "Jesus"}
+	handle(santana, "levyraati aloita", "")
+	handle(jesus, "levyraati liity", "")
+	handle(santana, "levyraati jatka", "")
+	handle(santana, "levyraati esitä Song1 https://example.com/1", "")
+	handle(jesus, "levyraati esitä Song2 https://example.com/2", "")
+
+	handle(jesus, "levyraati arvioi", "voice-1")
+	if expected := "Give the rating of a voice review as its caption, or reply to it with: " +
+		"levyraati arvioi 0/10"; lastMessage() != expected {
+		t.Errorf("Voice review without a rating got %q, want %q", lastMessage(), expected)
+	}
+	handle(jesus, "levyraati arvioi 7/10", "voice-1")
+	handle(santana, "levyraati arvioi 8/10", "voice-2")
+	sent := strings.Join(mockBot.receivedMessages, "\n")
+	for _, expected := range []string{
+		"<b>Jesus</b> wrote: Transcript of OggS voice. The song rating was: 7/10",
+		"<b>Santana</b> wrote: (voice review). The song rating was: 8/10",
+	} {
+		if !strings.Contains(sent, expected) {
+			t.Errorf("Voice review %q wasn't revealed", expected)
+		}
+	}
+	if p.State() != StateInit {
+		t.Fatalf("State = %s, want %s", p.State(), StateInit)
+	}
+
+	voiceFile := "jukebox_jury_voice_" + now.Local().Format("2006-01-02T150405") + "_123.oga"
+	content, err := os.ReadFile(filepath.Join(resultsDir, voiceFile))
+	if err != nil || string(content) != "OggS voice" {
+		t.Errorf("Saved voice review %q: %v", content, err)
+	}
+	results, err := filepath.Glob(filepath.Join(resultsDir, "jukebox_jury_results_*.html"))
+	if err != nil || len(results) != 1 {
+		t.Fatalf("Expected a single results file, got %v: %v", results, err)
+	}
+	html, err := os.ReadFile(results[0])
+	if err != nil {
+		t.Fatalf("Failed to read results: %v", err)
+	}
+	for _, expected := range []string{
+		`<audio controls src="` + voiceFile + `"></audio>`,
+		"<strong>Review:</strong> (voice review)",
+	} {
+		if !strings.Contains(string(html), expected) {
+			t.Errorf("Results don't contain %q", expected)
+		}
+	}
+
+	// Replays don't have the voice notes but get the logged transcripts
+	logs, err := filepath.Glob(filepath.Join(logDir, "jukebox_jury_events_*.jsonl"))
+	if err != nil || len(logs) != 1 {
+		t.Fatalf("Expected a single event log, got %v: %v", logs, err)
+	}
+	fin, err := os.Open(logs[0])
+	if err != nil {
+		t.Fatalf("Failed to open event log: %v", err)
+	}
+	defer fin.Close()
+	events, err := ReadEvents(fin)
+	if err != nil {
+		t.Fatalf("Failed to read event log: %v", err)
+	}
+	var out bytes.Buffer
+	if _, err = Replay(events, telegram.NewFakeBot(&out), WithOutputDirectory(nil), withoutShuffle()); err != nil {
+		t.Fatalf("Replay diverged: %v", err)
+	}
+	for _, expected := range []string{
+		"<b>Jesus</b> wrote: Transcript of OggS voice. The song rating was: 7/10",
+		"<b>Santana</b> wrote: (voice review). The song rating was: 8/10",
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Replay didn't reveal %q:\n%s", expected, out.String())
+		}
+	}
+}
+
+func TestVoiceReviewInBackground(t *testing.T) {
+	mockBot := mockTelegramBot{
+		mSend: func(_ tgbotapi.Chattable) (tgbotapi.Message, error) {
+			return tgbotapi.Message{}, nil
+		},
+		receivedMessages: []string{},
+	}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	transcriber := TranscriberFunc(func(_ context.Context, _ string) (string, error) {
+		close(started)
+		<-release
+		return "Slow transcript", nil
+	})
+	engine := NewEngine(New(&mockBot, 1,
+		WithOutputDirectory(nil),
+		withoutShuffle(),
+		WithTranscriber(transcriber, 0),
+	))
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	runErr := make(chan error)
+	go func() { runErr <- engine.Run(ctx) }()
+
+	submit := func(from *tgbotapi.User, text string, voice string) {
+		t.Helper()
+		update := testMessage(from, text)
+		if voice != "" {
+			update.Text, update.Caption = "", text
+			update.Voice = &tgbotapi.Voice{FileID: voice}
+		}
+		msg, err := ParseToMessage(tgbotapi.Update{Message: &update})
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %#v", text, err)
+		}
+		if err = engine.Submit(ctx, msg); err != nil {
+			t.Fatalf("Submit() failed: %v", err)
+		}
+	}
+	santana := &tgbotapi.User{ID: 666, UserName: "Santana"}
+	jesus := &tgbotapi.User{ID: 123, UserName: "Jesus"}
+	submit(santana, "levyraati aloita", "")
+	submit(jesus, "levyraati liity", "")
+	submit(santana, "levyraati jatka", "")
+	submit(santana, "levyraati esitä Song1 https://example.com/1", "")
+	submit(jesus, "levyraati esitä Song2 https://example.com/2", "")
+	submit(jesus, "levyraati arvioi 7/10", "voice-1")
+	select {
+	case <-started:
+	case <-ctx.Done():
+		t.Fatal("Voice review wasn't transcribed")
+	}
+
+	// The game goes on while the voice review is transcribed
+	submit(jesus, "levyraati arvioi Again 5/10", "")
+	snap, err := engine.Snapshot(ctx)
+	if err != nil {
+		t.Fatalf("Snapshot() failed: %v", err)
+	}
+	if snap.State != StateWaitForReviews || len(snap.Panelists[0].ReceivedReviews) != 0 {
+		t.Fatalf("Review was added before the transcript: %+v", snap)
+	}
+
+	close(release)
+	for len(snap.Panelists[0].ReceivedReviews) == 0 {
+		if snap, err = engine.Snapshot(ctx); err != nil {
+			t.Fatalf("Snapshot() failed: %v", err)
+		}
+	}
+	if review := snap.Panelists[0].ReceivedReviews; len(review) != 1 || review[0].Review != "Slow transcript" {
+		t.Errorf("Reviews of the song = %+v, want the transcribed voice review", review)
+	}
+
+	cancel()
+	<-runErr
+	sent := strings.Join(mockBot.receivedMessages, "\n")
+	for _, expected := range []string{
+		"You have already reviewed this song",
+		"<b>Jesus</b> wrote: Slow transcript. The song rating was: 7/10",
+	} {
+		if !strings.Contains(sent, expected) {
+			t.Errorf("Message %q wasn't sent:\n%s", expected, sent)
+		}
+	}
+}
+
+func TestVoiceReviewFinishedOnShutdown(t *testing.T) {
+	mockBot := mockTelegramBot{
+		mSend: func(_ tgbotapi.Chattable) (tgbotapi.Message, error) {
+			return tgbotapi.Message{}, nil
+		},
+		receivedMessages: []string{},
+	}
+	started := make(chan struct{})
+	release := make(chan struct{})
+	transcriber := TranscriberFunc(func(_ context.Context, _ string) (string, error) {
+		close(started)
+		<-release
+		return "Slow transcript", nil
+	})
+	engine := NewEngine(New(&mockBot, 1,
+		WithOutputDirectory(nil),
+		withoutShuffle(),
+		WithTranscriber(transcriber, 0),
+	))
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error)
+	go func() { runErr <- engine.Run(ctx) }()
+
+	santana := &tgbotapi.User{ID: 666, UserName: "Santana"}
+	jesus := &tgbotapi.User{ID: 123, UserName: "Jesus"}
+	review := testMessage(jesus, "levyraati arvioi 7/10")
+	review.Text, review.Caption = "", review.Text
+	review.Voice = &tgbotapi.Voice{FileID: "voice-1"}
+	for _, update := range []tgbotapi.Message{
+		testMessage(santana, "levyraati aloita"),
+		testMessage(jesus, "levyraati liity"),
+		testMessage(santana, "levyraati jatka"),
+		testMessage(santana, "levyraati esitä Song1 https://example.com/1"),
+		testMessage(jesus, "levyraati esitä Song2 https://example.com/2"),
+		review,
+	} {
+		msg, err := ParseToMessage(tgbotapi.Update{Message: &update})
+		if err != nil {
+			t.Fatalf("Failed to parse %q: %#v", update.Text, err)
+		}
+		if err = engine.Submit(ctx, msg); err != nil {
+			t.Fatalf("Submit() failed: %v", err)
+		}
+	}
+	<-started
+
+	// Engine waits for the transcription before interrupting the game
+	cancel()
+	select {
+	case <-runErr:
+		t.Fatal("Engine stopped before the voice review was transcribed")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	<-runErr
+
+	sent := mockBot.receivedMessages
+	reviewed := slices.IndexFunc(sent, func(m string) bool {
+		return strings.HasPrefix(m, "<b>Jesus</b> wrote: Slow transcript. The song rating was: 7/10")
+	})
+	interrupted := slices.Index(sent, "The bot is going down, the game was interrupted")
+	if reviewed == -1 || interrupted < reviewed {
+		t.Errorf("Voice review wasn't finished before the shutdown:\n%s", strings.Join(sent, "\n"))
+	}
+}
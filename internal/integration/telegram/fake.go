@@ -0,0 +1,107 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// FakeBot implements Boter by printing the sent messages instead of
+// sending them to Telegram. Polls and ChatAdmins are served to the
+// caller in place of the real Telegram responses.
+type FakeBot struct {
+	out        io.Writer
+	Polls      []tgbotapi.Poll
+	ChatAdmins []tgbotapi.ChatMember
+	messageID  int
+}
+
+func NewFakeBot(out io.Writer) *FakeBot {
+	return &FakeBot{out: out}
+}
+
+func (f *FakeBot) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	f.messageID++
+	if err := f.print(c); err != nil {
+		return tgbotapi.Message{}, fmt.Errorf("fake send: %w", err)
+	}
+
+	return tgbotapi.Message{MessageID: f.messageID}, nil
+}
+
+// print prints the text of the messages and edits, and a line about the
+// rest of the user-visible requests. Requests which users don't see, such
+// as the answers to button presses, aren't printed.
+func (f *FakeBot) print(c tgbotapi.Chattable) error {
+	var err error
+	switch msg := c.(type) {
+	case tgbotapi.MessageConfig:
+		_, err = fmt.Fprintf(f.out, "[chat %d] %s\n", msg.ChatID, msg.Text)
+	case tgbotapi.AudioConfig:
+		_, err = fmt.Fprintf(f.out, "[chat %d] Audio: %s\n", msg.ChatID, msg.Caption)
+	case tgbotapi.VoiceConfig:
+		_, err = fmt.Fprintf(f.out, "[chat %d] Voice: %s\n", msg.ChatID, msg.Caption)
+	case tgbotapi.SendPollConfig:
+		_, err = fmt.Fprintf(f.out, "[chat %d] Poll: %s (%s)\n",
+			msg.ChatID, msg.Question, strings.Join(msg.Options, ", "))
+	case tgbotapi.EditMessageTextConfig:
+		_, err = fmt.Fprintf(f.out, "[chat %d] Edited message %d: %s\n", msg.ChatID, msg.MessageID, msg.Text)
+	case tgbotapi.PinChatMessageConfig:
+		_, err = fmt.Fprintf(f.out, "[chat %d] Pinned message %d\n", msg.ChatID, msg.MessageID)
+	case tgbotapi.UnpinChatMessageConfig:
+		_, err = fmt.Fprintf(f.out, "[chat %d] Unpinned message %d\n", msg.ChatID, msg.MessageID)
+	case tgbotapi.CallbackConfig, tgbotapi.SetMyCommandsConfig:
+	default:
+		_, err = fmt.Fprintf(f.out, "%T\n", c)
+	}
+	return err
+}
+
+// GetUpdatesChan returns a closed channel, fake bot doesn't receive updates.
+func (f *FakeBot) GetUpdatesChan(_ tgbotapi.UpdateConfig) tgbotapi.UpdatesChannel {
+	ch := make(chan tgbotapi.Update)
+	close(ch)
+	return ch
+}
+
+func (f *FakeBot) GetChatAdministrators(_ tgbotapi.ChatAdministratorsConfig) ([]tgbotapi.ChatMember, error) {
+	return f.ChatAdmins, nil
+}
+
+// Request prints the requests like Send and accepts them.
+func (f *FakeBot) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	if err := f.print(c); err != nil {
+		return nil, fmt.Errorf("fake request: %w", err)
+	}
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+// MakeRequest prints the sent messages and accepts the rest of the requests.
+func (f *FakeBot) MakeRequest(endpoint string, params tgbotapi.Params) (*tgbotapi.APIResponse, error) {
+	if endpoint != "sendMessage" {
+		return &tgbotapi.APIResponse{Ok: true}, nil
+	}
+	if _, err := fmt.Fprintf(f.out, "[chat %s] %s\n", params["chat_id"], params["text"]); err != nil {
+		return nil, fmt.Errorf("fake request: %w", err)
+	}
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+// GetFileDirectURL fails, fake bot doesn't receive files.
+func (f *FakeBot) GetFileDirectURL(fileID string) (string, error) {
+	return "", errors.New("fake bot has no file " + fileID)
+}
+
+// StopPoll returns the queued polls in order. Empty poll is returned
+// when the queue is exhausted.
+func (f *FakeBot) StopPoll(_ tgbotapi.StopPollConfig) (tgbotapi.Poll, error) {
+	if len(f.Polls) == 0 {
+		return tgbotapi.Poll{}, nil
+	}
+	poll := f.Polls[0]
+	f.Polls = f.Polls[1:]
+	return poll, nil
+}
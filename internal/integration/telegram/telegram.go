@@ -7,4 +7,12 @@ import (
 type Boter interface {
 	Send(c tgbotapi.Chattable) (tgbotapi.Message, error)
 	GetUpdatesChan(config tgbotapi.UpdateConfig) tgbotapi.UpdatesChannel
+	GetChatAdministrators(config tgbotapi.ChatAdministratorsConfig) ([]tgbotapi.ChatMember, error)
+	StopPoll(config tgbotapi.StopPollConfig) (tgbotapi.Poll, error)
+	Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error)
+	// MakeRequest calls Bot API methods with parameters which the
+	// library doesn't support yet, such as link preview options.
+	MakeRequest(endpoint string, params tgbotapi.Params) (*tgbotapi.APIResponse, error)
+	// GetFileDirectURL returns the download URL of a file sent to the bot.
+	GetFileDirectURL(fileID string) (string, error)
 }
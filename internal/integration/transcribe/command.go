@@ -0,0 +1,47 @@
+package transcribe
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Command transcribes audio files with a local program, such as whisper.cpp.
+// The path of the audio file is appended to the arguments and the program
+// prints the transcript to stdout. Programs which don't read Telegram's Ogg
+// Opus voice notes can be wrapped in a script which converts them first.
+type Command struct {
+	args []string
+}
+
+// NewCommand parses the command line of the program. Arguments are
+// separated by whitespace.
+func NewCommand(commandLine string) (Command, error) {
+	args := strings.Fields(commandLine)
+	if len(args) == 0 {
+		return Command{}, errors.New("empty transcription command")
+	}
+	return Command{args: args}, nil
+}
+
+// Transcribe runs the program on the audio file. Whitespace of the
+// transcript is collapsed into single spaces.
+func (c Command) Transcribe(ctx context.Context, audio string) (string, error) {
+	if audio == "" {
+		return "", errors.New("no audio file to transcribe")
+	}
+
+	//nolint:gosec // The command comes from the bot's configuration
+	cmd := exec.CommandContext(ctx, c.args[0], append(c.args[1:], audio)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("transcribe %q: %w: %s", audio, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.Join(strings.Fields(string(out)), " "), nil
+}
@@ -0,0 +1,31 @@
+package transcribe
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	cmd, err := NewCommand("echo  Great\tsong, listen to")
+	if err != nil {
+		t.Fatalf("NewCommand() error: %v", err)
+	}
+	got, err := cmd.Transcribe(context.Background(), "review.oga")
+	if err != nil {
+		t.Fatalf("Transcribe() error: %v", err)
+	}
+	if want := "Great song, listen to review.oga"; got != want {
+		t.Errorf("Transcribe() = %q, want %q", got, want)
+	}
+
+	if _, err = NewCommand(" "); err == nil {
+		t.Error("NewCommand() accepted an empty command")
+	}
+	failing, _ := NewCommand("false")
+	if _, err = failing.Transcribe(context.Background(), "review.oga"); err == nil {
+		t.Error("Transcribe() succeeded with a failing command")
+	}
+	if _, err = cmd.Transcribe(context.Background(), ""); err == nil {
+		t.Error("Transcribe() succeeded without an audio file")
+	}
+}
@@ -0,0 +1,191 @@
+// Package tournament runs a knockout tournament between the songs of
+// previous games. The songs are seeded by their scores and the winners
+// of the head-to-head matches advance until one song is left.
+package tournament
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ErrTooFewSongs is returned when there aren't enough songs for a match.
+var ErrTooFewSongs = errors.New("tournament needs at least two songs")
+
+// Entry is a song competing in the tournament.
+type Entry struct {
+	PlayedAt    time.Time `json:"played_at"`
+	Panelist    string    `json:"panelist"`
+	Description string    `json:"description"`
+	URL         string    `json:"url"`
+	Score       float64   `json:"score"` // Average score in the game the song was played
+	PanelistID  int64     `json:"panelist_id"`
+	Seed        int       `json:"seed"`
+}
+
+// Match is a head-to-head between two songs, which are referred to by
+// their seeds. A zero seed is a song yet to be decided by an earlier
+// match, or a bye in the first round.
+type Match struct {
+	Seeds  [2]int `json:"seeds"`
+	Votes  [2]int `json:"votes"`
+	Winner int    `json:"winner"` // Seed of the winner, zero until decided
+}
+
+// Decided tells whether the winner of the match is known.
+func (m *Match) Decided() bool {
+	return m.Winner != 0
+}
+
+// Bracket is the state of a tournament. It's saved between the game
+// nights as JSON.
+type Bracket struct {
+	CreatedAt time.Time  `json:"created_at"`
+	Entries   []Entry    `json:"entries"` // By seed, the first is the first seed
+	Rounds    [][]*Match `json:"rounds"`  // The last round is the final
+}
+
+// New seeds the songs by their scores and draws the bracket. The best
+// seeds get a bye when the number of songs isn't a power of two.
+func New(entries []Entry, now time.Time) (*Bracket, error) {
+	if len(entries) < 2 {
+		return nil, fmt.Errorf("%w, got %d", ErrTooFewSongs, len(entries))
+	}
+
+	b := &Bracket{CreatedAt: now, Entries: slices.Clone(entries)}
+	slices.SortStableFunc(b.Entries, func(a, b Entry) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return a.PlayedAt.Compare(b.PlayedAt)
+	})
+	for i := range b.Entries {
+		b.Entries[i].Seed = i + 1
+	}
+
+	order := seedOrder(len(b.Entries))
+	for size := len(order); size > 1; size /= 2 {
+		round := make([]*Match, size/2)
+		for i := range round {
+			round[i] = &Match{}
+		}
+		b.Rounds = append(b.Rounds, round)
+	}
+	for i, match := range b.Rounds[0] {
+		for slot, seed := range order[2*i : 2*i+2] {
+			if seed <= len(b.Entries) {
+				match.Seeds[slot] = seed
+			}
+		}
+		// Byes are never paired with each other
+		if match.Seeds[0] == 0 || match.Seeds[1] == 0 {
+			b.advance(0, i, max(match.Seeds[0], match.Seeds[1]))
+		}
+	}
+
+	return b, nil
+}
+
+// Validate checks that the bracket has the shape of a drawn one, so that
+// a damaged bracket loaded from a file is rejected before it's played.
+func (b *Bracket) Validate() error {
+	if len(b.Entries) < 2 {
+		return fmt.Errorf("%w, got %d", ErrTooFewSongs, len(b.Entries))
+	}
+	if len(b.Rounds) == 0 {
+		return errors.New("bracket has no rounds")
+	}
+	for round, matches := range b.Rounds {
+		if want := 1 << (len(b.Rounds) - round - 1); len(matches) != want {
+			return fmt.Errorf("round %d has %d matches, want %d", round+1, len(matches), want)
+		}
+		for i, match := range matches {
+			if match == nil {
+				return fmt.Errorf("round %d match %d is missing", round+1, i+1)
+			}
+			for _, seed := range []int{match.Seeds[0], match.Seeds[1], match.Winner} {
+				if seed < 0 || seed > len(b.Entries) {
+					return fmt.Errorf("round %d match %d has unknown seed %d", round+1, i+1, seed)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// seedOrder returns the seeds of the first round in the order they are
+// paired, so that the best seeds meet as late as possible. The number of
+// seeds is rounded up to a power of two.
+func seedOrder(entries int) []int {
+	order := []int{1}
+	for len(order) < entries {
+		next := make([]int, 0, 2*len(order))
+		for _, seed := range order {
+			next = append(next, seed, 2*len(order)+1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// Entry returns the song with the seed, or nil for a zero seed.
+func (b *Bracket) Entry(seed int) *Entry {
+	if seed < 1 || seed > len(b.Entries) {
+		return nil
+	}
+	return &b.Entries[seed-1]
+}
+
+// Next returns the next match to be played and its round, counted from
+// zero. Returns nil when the tournament is over.
+func (b *Bracket) Next() (*Match, int) {
+	for round, matches := range b.Rounds {
+		for _, match := range matches {
+			if !match.Decided() && match.Seeds[0] != 0 && match.Seeds[1] != 0 {
+				return match, round
+			}
+		}
+	}
+	return nil, 0
+}
+
+// Decide records the votes of the match and advances its winner. A tie
+// is won by the better seed.
+func (b *Bracket) Decide(match *Match, votes [2]int) (*Entry, error) {
+	for round, matches := range b.Rounds {
+		index := slices.Index(matches, match)
+		if index == -1 {
+			continue
+		}
+		if match.Decided() {
+			return nil, fmt.Errorf("round %d match %d is already decided", round+1, index+1)
+		}
+		match.Votes = votes
+		winner := min(match.Seeds[0], match.Seeds[1])
+		switch {
+		case votes[0] > votes[1]:
+			winner = match.Seeds[0]
+		case votes[1] > votes[0]:
+			winner = match.Seeds[1]
+		}
+		b.advance(round, index, winner)
+		return b.Entry(winner), nil
+	}
+	return nil, errors.New("match is not in the bracket")
+}
+
+// advance sets the winner of the match and moves it to the next round.
+func (b *Bracket) advance(round int, index int, winner int) {
+	b.Rounds[round][index].Winner = winner
+	if round+1 < len(b.Rounds) {
+		b.Rounds[round+1][index/2].Seeds[index%2] = winner
+	}
+}
+
+// Champion returns the winner of the final, or nil until it's decided.
+func (b *Bracket) Champion() *Entry {
+	final := b.Rounds[len(b.Rounds)-1][0]
+	return b.Entry(final.Winner)
+}
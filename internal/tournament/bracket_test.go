@@ -0,0 +1,203 @@
+package tournament
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func testEntries() []Entry {
+	played := time.Date(2024, 10, 18, 20, 0, 0, 0, time.UTC)
+	entries := []Entry{}
+	for i, score := range []float64{6, 9, 4, 7, 5} {
+		entries = append(entries, Entry{
+			PlayedAt:    played.Add(time.Duration(i) * time.Hour),
+			Panelist:    string(rune('A' + i)),
+			Description: "Song" + string(rune('A'+i)),
+			URL:         "https://example.com/" + string(rune('a'+i)),
+			Score:       score,
+			PanelistID:  int64(i + 1),
+		})
+	}
+	return entries
+}
+
+func TestNewSeedsByScore(t *testing.T) {
+	b, err := New(testEntries(), time.Time{})
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	var seeded []string
+	for _, entry := range b.Entries {
+		seeded = append(seeded, entry.Panelist)
+	}
+	if diff := cmp.Diff([]string{"B", "D", "A", "E", "C"}, seeded); diff != "" {
+		t.Errorf("Seeding mismatch (-want +got):\n%s", diff)
+	}
+
+	// The three best seeds get a bye into the semifinals
+	want := [][]*Match{
+		{
+			{Seeds: [2]int{1, 0}, Winner: 1},
+			{Seeds: [2]int{4, 5}},
+			{Seeds: [2]int{2, 0}, Winner: 2},
+			{Seeds: [2]int{3, 0}, Winner: 3},
+		},
+		{{Seeds: [2]int{1, 0}}, {Seeds: [2]int{2, 3}}},
+		{{}},
+	}
+	if diff := cmp.Diff(want, b.Rounds); diff != "" {
+		t.Errorf("Bracket mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, err = New(testEntries()[:1], time.Time{}); !errors.Is(err, ErrTooFewSongs) {
+		t.Errorf("New() with one song error = %v, want %v", err, ErrTooFewSongs)
+	}
+}
+
+func TestPlayTournament(t *testing.T) {
+	b, err := New(testEntries(), time.Time{})
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	tests := []struct {
+		seeds  [2]int
+		votes  [2]int
+		round  int
+		winner int
+	}{
+		{seeds: [2]int{4, 5}, votes: [2]int{1, 3}, round: 0, winner: 5},
+		{seeds: [2]int{1, 5}, votes: [2]int{2, 2}, round: 1, winner: 1}, // Tie goes to the better seed
+		{seeds: [2]int{2, 3}, votes: [2]int{0, 4}, round: 1, winner: 3},
+		{seeds: [2]int{1, 3}, votes: [2]int{3, 1}, round: 2, winner: 1},
+	}
+	for i, tt := range tests {
+		if b.Champion() != nil {
+			t.Fatalf("Match %d: champion before the final", i)
+		}
+		match, round := b.Next()
+		if match == nil || match.Seeds != tt.seeds || round != tt.round {
+			t.Fatalf("Match %d: Next() = %+v in round %d, want %v in round %d",
+				i, match, round, tt.seeds, tt.round)
+		}
+		winner, err := b.Decide(match, tt.votes)
+		if err != nil {
+			t.Fatalf("Match %d: Decide() error: %v", i, err)
+		}
+		if winner.Seed != tt.winner {
+			t.Errorf("Match %d: winner = %d, want %d", i, winner.Seed, tt.winner)
+		}
+		if _, err = b.Decide(match, tt.votes); err == nil {
+			t.Errorf("Match %d: deciding twice succeeded", i)
+		}
+	}
+
+	if match, _ := b.Next(); match != nil {
+		t.Errorf("Next() = %+v after the final", match)
+	}
+	if champion := b.Champion(); champion == nil || champion.Panelist != "B" {
+		t.Errorf("Champion() = %+v, want B", champion)
+	}
+}
+
+func TestBracketJSON(t *testing.T) {
+	b, err := New(testEntries(), time.Date(2024, 10, 18, 20, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	match, _ := b.Next()
+	if _, err = b.Decide(match, [2]int{3, 1}); err != nil {
+		t.Fatalf("Decide() error: %v", err)
+	}
+
+	content, err := json.Marshal(b)
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	var loaded Bracket
+	if err = json.Unmarshal(content, &loaded); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	if diff := cmp.Diff(b, &loaded); diff != "" {
+		t.Errorf("Loaded bracket mismatch (-want +got):\n%s", diff)
+	}
+
+	// The loaded bracket continues where the saved one left off
+	match, round := loaded.Next()
+	if match == nil || match.Seeds != [2]int{1, 4} || round != 1 {
+		t.Errorf("Next() = %+v in round %d, want [1 4] in round 1", match, round)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	drawn := func() *Bracket {
+		b, err := New(testEntries(), time.Time{})
+		if err != nil {
+			t.Fatalf("New() error: %v", err)
+		}
+		return b
+	}
+	if err := drawn().Validate(); err != nil {
+		t.Errorf("Validate() of a drawn bracket: %v", err)
+	}
+
+	tests := []struct {
+		damage func(b *Bracket)
+		name   string
+		want   string
+	}{
+		{name: "No entries", damage: func(b *Bracket) { b.Entries = nil }, want: "at least two songs"},
+		{name: "No rounds", damage: func(b *Bracket) { b.Rounds = nil }, want: "no rounds"},
+		{name: "No final", damage: func(b *Bracket) { b.Rounds[2] = nil }, want: "round 3 has 0 matches"},
+		{
+			name:   "Missing match",
+			damage: func(b *Bracket) { b.Rounds[1][1] = nil },
+			want:   "round 2 match 2 is missing",
+		},
+		{name: "Unknown seed", damage: func(b *Bracket) { b.Rounds[0][0].Winner = 9 }, want: "unknown seed 9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := drawn()
+			tt.damage(b)
+			if err := b.Validate(); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	b, err := New(testEntries(), time.Time{})
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	match, _ := b.Next()
+	if _, err = b.Decide(match, [2]int{1, 2}); err != nil {
+		t.Fatalf("Decide() error: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err = b.Render(&out); err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+	for _, expected := range []string{
+		"<h2>Semifinals</h2>",
+		"<h2>Final</h2>",
+		`<p class="bye">Bye</p>`,
+		`<p class="tbd">To be decided</p>`,
+		`<a href="https://example.com/c" target="_blank">SongC</a>`,
+		`<span class="votes">2</span>`,
+	} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("Bracket doesn't contain %q:\n%s", expected, out.String())
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package tournament
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+)
+
+//go:embed assets/bracket_template.html
+var bracketTemplate string
+
+var tmpl = template.Must(template.New("bracket").Parse(bracketTemplate))
+
+// roundView is a round of the bracket as shown in the HTML.
+type roundView struct {
+	Name    string
+	Matches [][2]slotView
+}
+
+// slotView is a song of a match resolved from its seed.
+type slotView struct {
+	Song   *Entry // Nil for a bye or a song yet to be decided
+	Votes  int
+	Played bool // The match was decided by votes
+	Won    bool
+	Lost   bool
+	Bye    bool
+}
+
+// roundName names the round by how far it is from the final.
+func roundName(round int, rounds int) string {
+	switch rounds - round {
+	case 1:
+		return "Final"
+	case 2:
+		return "Semifinals"
+	case 3:
+		return "Quarterfinals"
+	}
+	return fmt.Sprintf("Round %d", round+1)
+}
+
+// Render renders the bracket as an HTML page, which is served next to
+// the game results and shares their style sheet.
+func (b *Bracket) Render(output io.Writer) error {
+	rounds := make([]roundView, 0, len(b.Rounds))
+	for i, matches := range b.Rounds {
+		round := roundView{Name: roundName(i, len(b.Rounds))}
+		for _, match := range matches {
+			var slots [2]slotView
+			played := match.Decided() && match.Seeds[0] != 0 && match.Seeds[1] != 0
+			for slot, seed := range match.Seeds {
+				slots[slot] = slotView{
+					Song:   b.Entry(seed),
+					Votes:  match.Votes[slot],
+					Played: played,
+					Won:    match.Decided() && match.Winner == seed,
+					Lost:   match.Decided() && match.Winner != seed,
+					Bye:    i == 0 && seed == 0,
+				}
+			}
+			round.Matches = append(round.Matches, slots)
+		}
+		rounds = append(rounds, round)
+	}
+
+	data := struct {
+		Champion *Entry
+		Rounds   []roundView
+	}{Champion: b.Champion(), Rounds: rounds}
+	if err := tmpl.Execute(output, data); err != nil {
+		return fmt.Errorf("rendering bracket: %w", err)
+	}
+	return nil
+}